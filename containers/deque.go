@@ -0,0 +1,99 @@
+package containers
+
+// Deque is a double-ended queue backed by a ring buffer that grows as needed.
+// Popped slots are cleared, so the deque never keeps removed values alive.
+type Deque[T any] struct {
+	buf  []T
+	head int
+	size int
+}
+
+func NewDeque[T any](capacity int) *Deque[T] {
+	return &Deque[T]{buf: make([]T, max(capacity, 1))}
+}
+
+func (d *Deque[T]) PushBack(v T) {
+	d.grow()
+	d.buf[(d.head+d.size)%len(d.buf)] = v
+	d.size++
+}
+
+func (d *Deque[T]) PushFront(v T) {
+	d.grow()
+	d.head = (d.head - 1 + len(d.buf)) % len(d.buf)
+	d.buf[d.head] = v
+	d.size++
+}
+
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	v := d.buf[d.head]
+	d.buf[d.head] = zero
+	d.head = (d.head + 1) % len(d.buf)
+	d.size--
+	return v, true
+}
+
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	idx := (d.head + d.size - 1) % len(d.buf)
+	v := d.buf[idx]
+	d.buf[idx] = zero
+	d.size--
+	return v, true
+}
+
+func (d *Deque[T]) Front() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	return d.buf[d.head], true
+}
+
+func (d *Deque[T]) Back() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	return d.buf[(d.head+d.size-1)%len(d.buf)], true
+}
+
+// At returns the i-th element counting from the front.
+func (d *Deque[T]) At(i int) (T, bool) {
+	var zero T
+	if i < 0 || i >= d.size {
+		return zero, false
+	}
+	return d.buf[(d.head+i)%len(d.buf)], true
+}
+
+func (d *Deque[T]) Len() int {
+	return d.size
+}
+
+func (d *Deque[T]) IsEmpty() bool {
+	return d.size == 0
+}
+
+// grow doubles the buffer when it is full, unrolling the ring so the front
+// ends up at index 0.
+func (d *Deque[T]) grow() {
+	if len(d.buf) == 0 {
+		d.buf = make([]T, 1)
+	}
+	if d.size < len(d.buf) {
+		return
+	}
+	buf := make([]T, 2*len(d.buf))
+	n := copy(buf, d.buf[d.head:])
+	copy(buf[n:], d.buf[:d.head])
+	d.buf = buf
+	d.head = 0
+}
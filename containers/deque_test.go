@@ -0,0 +1,88 @@
+package containers
+
+import (
+	"slices"
+	"testing"
+)
+
+func dequeItems[T any](d *Deque[T]) []T {
+	var out []T
+	for i := range d.Len() {
+		v, _ := d.At(i)
+		out = append(out, v)
+	}
+	return out
+}
+
+func TestDequePushFrontAfterWrap(t *testing.T) {
+	d := NewDeque[int](4)
+	d.PushBack(2)
+	d.PushBack(3)
+	// the head is at index 0, so PushFront wraps to the end of the buffer
+	d.PushFront(1)
+	d.PushFront(0)
+	if d.head != 2 || len(d.buf) != 4 {
+		t.Fatalf("head = %d, len(buf) = %d, want 2, 4", d.head, len(d.buf))
+	}
+
+	want := []int{0, 1, 2, 3}
+	if got := dequeItems(d); !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// full buffer with a wrapped head: the next push grows it and has to
+	// unroll the ring
+	d.PushFront(-1)
+	d.PushBack(4)
+	if len(d.buf) != 8 {
+		t.Fatalf("len(buf) = %d, want 8", len(d.buf))
+	}
+	want = []int{-1, 0, 1, 2, 3, 4}
+	if got := dequeItems(d); !slices.Equal(got, want) {
+		t.Fatalf("after grow got %v, want %v", got, want)
+	}
+
+	for _, w := range want {
+		v, ok := d.PopFront()
+		if !ok || v != w {
+			t.Fatalf("PopFront() = %v, %v, want %v, true", v, ok, w)
+		}
+	}
+	if _, ok := d.PopBack(); ok {
+		t.Fatal("PopBack() on empty deque returned ok")
+	}
+}
+
+func TestDequeMatchesSlice(t *testing.T) {
+	var d Deque[int]
+	var want []int
+	for i := range 1000 {
+		switch i % 7 {
+		case 0, 3:
+			d.PushFront(i)
+			want = slices.Insert(want, 0, i)
+		case 1, 4, 5:
+			d.PushBack(i)
+			want = append(want, i)
+		case 2:
+			v, ok := d.PopFront()
+			if ok != (len(want) > 0) || (ok && v != want[0]) {
+				t.Fatalf("step %d: PopFront() = %v, %v, want %v", i, v, ok, want)
+			}
+			if ok {
+				want = want[1:]
+			}
+		case 6:
+			v, ok := d.PopBack()
+			if ok != (len(want) > 0) || (ok && v != want[len(want)-1]) {
+				t.Fatalf("step %d: PopBack() = %v, %v, want %v", i, v, ok, want)
+			}
+			if ok {
+				want = want[:len(want)-1]
+			}
+		}
+		if got := dequeItems(&d); !slices.Equal(got, want) {
+			t.Fatalf("step %d: got %v, want %v", i, got, want)
+		}
+	}
+}
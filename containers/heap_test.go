@@ -0,0 +1,51 @@
+package containers
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestHeapCustomLess(t *testing.T) {
+	type job struct {
+		name     string
+		priority int
+	}
+	// highest priority first
+	h := NewHeap(func(a, b job) bool { return a.priority > b.priority })
+	for _, j := range []job{{"low", 1}, {"high", 9}, {"mid", 5}, {"top", 10}} {
+		h.Push(j)
+	}
+
+	if j, ok := h.Peek(); !ok || j.name != "top" {
+		t.Fatalf("Peek() = %v, %v, want top", j, ok)
+	}
+	for _, want := range []string{"top", "high", "mid", "low"} {
+		j, ok := h.Pop()
+		if !ok || j.name != want {
+			t.Fatalf("Pop() = %v, %v, want %s", j, ok, want)
+		}
+	}
+	if _, ok := h.Pop(); ok {
+		t.Fatal("Pop() on empty heap returned ok")
+	}
+}
+
+func TestHeapSorts(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := NewHeap(func(a, b int) bool { return a < b })
+	var want []int
+	for range 500 {
+		v := r.Intn(100)
+		h.Push(v)
+		want = append(want, v)
+	}
+	slices.Sort(want)
+
+	for i, w := range want {
+		v, ok := h.Pop()
+		if !ok || v != w {
+			t.Fatalf("pop %d = %v, %v, want %v", i, v, ok, w)
+		}
+	}
+}
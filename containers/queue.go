@@ -0,0 +1,30 @@
+package containers
+
+// Queue is a FIFO queue backed by a ring buffer.
+type Queue[T any] struct {
+	items Deque[T]
+}
+
+func NewQueue[T any](capacity int) *Queue[T] {
+	return &Queue[T]{items: *NewDeque[T](capacity)}
+}
+
+func (q *Queue[T]) Put(v T) {
+	q.items.PushBack(v)
+}
+
+func (q *Queue[T]) Pop() (T, bool) {
+	return q.items.PopFront()
+}
+
+func (q *Queue[T]) Peek() (T, bool) {
+	return q.items.Front()
+}
+
+func (q *Queue[T]) Len() int {
+	return q.items.Len()
+}
+
+func (q *Queue[T]) IsEmpty() bool {
+	return q.items.IsEmpty()
+}
@@ -0,0 +1,28 @@
+package containers
+
+import "testing"
+
+func TestQueueFIFO(t *testing.T) {
+	q := NewQueue[string](1)
+	for _, s := range []string{"a", "b", "c"} {
+		q.Put(s)
+	}
+	if v, ok := q.Peek(); !ok || v != "a" {
+		t.Fatalf("Peek() = %q, %v, want \"a\", true", v, ok)
+	}
+
+	q.Pop()
+	q.Put("d")
+	for _, want := range []string{"b", "c", "d"} {
+		v, ok := q.Pop()
+		if !ok || v != want {
+			t.Fatalf("Pop() = %q, %v, want %q, true", v, ok, want)
+		}
+	}
+	if !q.IsEmpty() {
+		t.Fatalf("queue has %d items left, want none", q.Len())
+	}
+	if _, ok := q.Pop(); ok {
+		t.Fatal("Pop() on empty queue returned ok")
+	}
+}
@@ -0,0 +1,56 @@
+package containers
+
+type Stack[T any] struct {
+	data []T
+}
+
+func NewStack[T any](capacity int) *Stack[T] {
+	return &Stack[T]{data: make([]T, 0, capacity)}
+}
+
+func (s *Stack[T]) Push(v T) {
+	s.data = append(s.data, v)
+}
+
+func (s *Stack[T]) Pop() T {
+	var zero T
+	if len(s.data) == 0 {
+		return zero
+	}
+	last := s.data[len(s.data)-1]
+	s.data[len(s.data)-1] = zero
+	s.data = s.data[:len(s.data)-1]
+	return last
+}
+
+func (s *Stack[T]) Top() T {
+	var zero T
+	if len(s.data) == 0 {
+		return zero
+	}
+	return s.data[len(s.data)-1]
+}
+
+func (s *Stack[T]) Len() int {
+	return len(s.data)
+}
+
+func (s *Stack[T]) IsEmpty() bool {
+	return len(s.data) == 0
+}
+
+// TrimLast removes the last n elements
+func (s *Stack[T]) TrimLast(n int) {
+	if n <= 0 || n > len(s.data) {
+		return
+	}
+	clear(s.data[len(s.data)-n:])
+	s.data = s.data[:len(s.data)-n]
+}
+
+// Slice returns a copy of the internal slice
+func (s *Stack[T]) Slice() []T {
+	out := make([]T, len(s.data))
+	copy(out, s.data)
+	return out
+}
@@ -0,0 +1,42 @@
+package containers
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStackTrimLast(t *testing.T) {
+	tests := []struct {
+		n    int
+		want []int
+	}{
+		{n: 0, want: []int{1, 2, 3, 4}},
+		{n: -1, want: []int{1, 2, 3, 4}},
+		{n: 5, want: []int{1, 2, 3, 4}},
+		{n: 1, want: []int{1, 2, 3}},
+		{n: 4, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		s := NewStack[int](4)
+		for i := 1; i <= 4; i++ {
+			s.Push(i)
+		}
+		s.TrimLast(tt.n)
+		if got := s.Slice(); !slices.Equal(got, tt.want) {
+			t.Errorf("TrimLast(%d) left %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestStackPushPop(t *testing.T) {
+	s := NewStack[int](0)
+	s.Push(1)
+	s.Push(2)
+	if s.Top() != 2 || s.Pop() != 2 || s.Pop() != 1 {
+		t.Fatal("stack is not LIFO")
+	}
+	if !s.IsEmpty() || s.Pop() != 0 {
+		t.Fatal("Pop() on empty stack did not return the zero value")
+	}
+}
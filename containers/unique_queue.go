@@ -0,0 +1,45 @@
+package containers
+
+// UniqueQueue is a FIFO queue that admits every value at most once: values
+// that were already put, even if popped since, are ignored. The zero value
+// is an empty queue ready to use.
+type UniqueQueue[T comparable] struct {
+	seen  map[T]bool
+	queue Queue[T]
+}
+
+func NewUniqueQueue[T comparable]() *UniqueQueue[T] {
+	return &UniqueQueue[T]{
+		seen:  make(map[T]bool),
+		queue: *NewQueue[T](0),
+	}
+}
+
+// Put enqueues v and reports whether it was the first time v was seen.
+func (uq *UniqueQueue[T]) Put(v T) bool {
+	if uq.seen[v] {
+		return false
+	}
+	if uq.seen == nil {
+		uq.seen = make(map[T]bool)
+	}
+	uq.seen[v] = true
+	uq.queue.Put(v)
+	return true
+}
+
+func (uq *UniqueQueue[T]) Pop() (T, bool) {
+	return uq.queue.Pop()
+}
+
+func (uq *UniqueQueue[T]) Seen(v T) bool {
+	return uq.seen[v]
+}
+
+func (uq *UniqueQueue[T]) Len() int {
+	return uq.queue.Len()
+}
+
+func (uq *UniqueQueue[T]) IsEmpty() bool {
+	return uq.queue.IsEmpty()
+}
@@ -0,0 +1,39 @@
+package containers
+
+import "testing"
+
+func TestUniqueQueueRePutAfterPop(t *testing.T) {
+	uq := NewUniqueQueue[int]()
+	if !uq.Put(1) || !uq.Put(2) {
+		t.Fatal("first Put of a value returned false")
+	}
+	if uq.Put(1) {
+		t.Fatal("Put of a queued value returned true")
+	}
+
+	if v, _ := uq.Pop(); v != 1 {
+		t.Fatalf("Pop() = %v, want 1", v)
+	}
+	if uq.Put(1) {
+		t.Fatal("Put of a popped value returned true")
+	}
+	if !uq.Seen(1) {
+		t.Fatal("Seen(1) = false after popping it")
+	}
+	if uq.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", uq.Len())
+	}
+}
+
+func TestUniqueQueueZeroValue(t *testing.T) {
+	var uq UniqueQueue[string]
+	if uq.Seen("a") {
+		t.Fatal("empty queue has seen a value")
+	}
+	if !uq.Put("a") || uq.Put("a") {
+		t.Fatal("zero value queue does not deduplicate")
+	}
+	if v, ok := uq.Pop(); !ok || v != "a" {
+		t.Fatalf("Pop() = %q, %v, want \"a\", true", v, ok)
+	}
+}
@@ -0,0 +1,123 @@
+package day2
+
+import (
+	"fmt"
+	"iter"
+	"math/big"
+	"slices"
+	"strings"
+)
+
+// BigProductIDRange is the arbitrary-precision version of ProductIDRange,
+// used when the IDs or their sum do not fit in an int.
+type BigProductIDRange struct {
+	First *big.Int
+	Last  *big.Int
+}
+
+func (idRange *BigProductIDRange) PartOneInvalidIDs() []*big.Int {
+	return idRange.InvalidIDs(partOneRule)
+}
+
+func (idRange *BigProductIDRange) PartTwoInvalidIDs() []*big.Int {
+	return idRange.InvalidIDs(partTwoRule)
+}
+
+// InvalidIDs returns the IDs in the range matched by rule.
+func (idRange *BigProductIDRange) InvalidIDs(rule RepeatRule) []*big.Int {
+	return slices.Collect(idRange.InvalidIDsSeq(rule))
+}
+
+func (idRange *BigProductIDRange) PartOneInvalidIDsSeq() iter.Seq[*big.Int] {
+	return idRange.InvalidIDsSeq(partOneRule)
+}
+
+func (idRange *BigProductIDRange) PartTwoInvalidIDsSeq() iter.Seq[*big.Int] {
+	return idRange.InvalidIDsSeq(partTwoRule)
+}
+
+// InvalidIDsSeq lazily yields the IDs in the range matched by rule, in
+// ascending order. Every yielded value is a fresh copy owned by the caller.
+func (idRange *BigProductIDRange) InvalidIDsSeq(rule RepeatRule) iter.Seq[*big.Int] {
+	first, last := idRange.First, idRange.Last
+	return func(yield func(*big.Int) bool) {
+		one := big.NewInt(1)
+		for id := new(big.Int).Set(first); id.Cmp(last) <= 0; id.Add(id, one) {
+			if rule.MatchBig(id) && !yield(new(big.Int).Set(id)) {
+				return
+			}
+		}
+	}
+}
+
+func sumBigInvalidIDs(data string, rule RepeatRule) (*big.Int, error) {
+	idRanges, err := parseBigProductIDRanges(data)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := new(big.Int)
+	for _, idRange := range idRanges {
+		for invalidID := range idRange.InvalidIDsSeq(rule) {
+			ans.Add(ans, invalidID)
+		}
+	}
+
+	return ans, nil
+}
+
+func parseBigProductIDRanges(data string) ([]BigProductIDRange, error) {
+	var ranges []BigProductIDRange
+	for i, rangeStr := range splitProductIDRanges(data) {
+		firstStr, lastStr, found := strings.Cut(rangeStr, "-")
+		if !found {
+			return nil, fmt.Errorf("range %d: invalid range: %v", i+1, rangeStr)
+		}
+
+		first, ok := new(big.Int).SetString(strings.TrimSpace(firstStr), 10)
+		if !ok {
+			return nil, fmt.Errorf("range %d: invalid first number in range %v", i+1, rangeStr)
+		}
+
+		last, ok := new(big.Int).SetString(strings.TrimSpace(lastStr), 10)
+		if !ok {
+			return nil, fmt.Errorf("range %d: invalid last number in range %v", i+1, rangeStr)
+		}
+
+		if last.Cmp(first) < 0 {
+			return nil, fmt.Errorf("range %d: last number is lower than first in range %v", i+1, rangeStr)
+		}
+
+		ranges = append(ranges, BigProductIDRange{
+			First: first,
+			Last:  last,
+		})
+	}
+
+	return normalizeBigProductIDRanges(ranges), nil
+}
+
+func normalizeBigProductIDRanges(ranges []BigProductIDRange) []BigProductIDRange {
+	slices.SortFunc(ranges, func(a, b BigProductIDRange) int {
+		return a.First.Cmp(b.First)
+	})
+
+	one := big.NewInt(1)
+	next := new(big.Int)
+
+	var merged []BigProductIDRange
+	for _, idRange := range ranges {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			if idRange.First.Cmp(next.Add(last.Last, one)) <= 0 {
+				if idRange.Last.Cmp(last.Last) > 0 {
+					last.Last = idRange.Last
+				}
+				continue
+			}
+		}
+		merged = append(merged, idRange)
+	}
+
+	return merged
+}
@@ -0,0 +1,145 @@
+package day2
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RepeatRule matches the numbers whose representation in Base is a single
+// block of digits repeated between MinRepeats and MaxRepeats times.
+// A MaxRepeats of 0 means there is no upper bound.
+type RepeatRule struct {
+	Base       int
+	MinRepeats int
+	MaxRepeats int
+}
+
+var (
+	partOneRule = RepeatedExactly(2)
+	partTwoRule = RepeatedAtLeast(2)
+)
+
+func RepeatedExactly(k int) RepeatRule {
+	return RepeatRule{Base: 10, MinRepeats: k, MaxRepeats: k}
+}
+
+func RepeatedAtLeast(k int) RepeatRule {
+	return RepeatRule{Base: 10, MinRepeats: k}
+}
+
+func RepeatedBetween(minK, maxK int) RepeatRule {
+	return RepeatRule{Base: 10, MinRepeats: minK, MaxRepeats: maxK}
+}
+
+// InBase returns a copy of the rule that works on base b representations.
+func (r RepeatRule) InBase(b int) RepeatRule {
+	r.Base = b
+	return r
+}
+
+func (r RepeatRule) Validate() error {
+	if r.Base < 2 || r.Base > 36 {
+		return fmt.Errorf("base should be between 2 and 36, got %d", r.Base)
+	}
+	if r.MinRepeats < 1 {
+		return fmt.Errorf("min repeats should be at least 1, got %d", r.MinRepeats)
+	}
+	if r.MaxRepeats != 0 && r.MaxRepeats < r.MinRepeats {
+		return fmt.Errorf("max repeats %d is lower than min repeats %d", r.MaxRepeats, r.MinRepeats)
+	}
+	return nil
+}
+
+func (r RepeatRule) Match(number int) bool {
+	if number < 0 {
+		return false
+	}
+	return r.matchDigits(strconv.FormatInt(int64(number), r.Base))
+}
+
+func (r RepeatRule) MatchBig(number *big.Int) bool {
+	if number.Sign() < 0 {
+		return false
+	}
+	return r.matchDigits(number.Text(r.Base))
+}
+
+func (r RepeatRule) matchDigits(s string) bool {
+	lenght := len(s)
+
+	maxRepeats := lenght
+	if r.MaxRepeats > 0 {
+		maxRepeats = min(maxRepeats, r.MaxRepeats)
+	}
+
+	for repeats := max(r.MinRepeats, 1); repeats <= maxRepeats; repeats++ {
+		if lenght%repeats != 0 {
+			continue
+		}
+		pattern := s[:lenght/repeats]
+
+		if strings.Repeat(pattern, repeats) == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r RepeatRule) String() string {
+	switch {
+	case r.MaxRepeats == 0:
+		return fmt.Sprintf("%d+ repeats in base %d", r.MinRepeats, r.Base)
+	case r.MinRepeats == r.MaxRepeats:
+		return fmt.Sprintf("%d repeats in base %d", r.MinRepeats, r.Base)
+	}
+	return fmt.Sprintf("%d-%d repeats in base %d", r.MinRepeats, r.MaxRepeats, r.Base)
+}
+
+// ParseRepeatRule parses "k" (exactly k), "k+" (at least k) and "a-b"
+// (between a and b) into a base 10 rule, and fails when the rule cannot
+// match anything, like "0" or "5-2".
+func ParseRepeatRule(s string) (RepeatRule, error) {
+	rule, err := parseRepeatRule(strings.TrimSpace(s))
+	if err != nil {
+		return RepeatRule{}, fmt.Errorf("invalid repeat rule %q: %w", s, err)
+	}
+	if err := rule.Validate(); err != nil {
+		return RepeatRule{}, fmt.Errorf("invalid repeat rule %q: %w", s, err)
+	}
+	return rule, nil
+}
+
+func parseRepeatRule(s string) (RepeatRule, error) {
+	if kStr, found := strings.CutSuffix(s, "+"); found {
+		k, err := strconv.Atoi(kStr)
+		if err != nil {
+			return RepeatRule{}, err
+		}
+		return RepeatedAtLeast(k), nil
+	}
+
+	if minStr, maxStr, found := strings.Cut(s, "-"); found {
+		minK, err := strconv.Atoi(minStr)
+		if err != nil {
+			return RepeatRule{}, err
+		}
+		maxK, err := strconv.Atoi(maxStr)
+		if err != nil {
+			return RepeatRule{}, err
+		}
+		if maxK == 0 {
+			// a MaxRepeats of 0 would mean no upper bound
+			return RepeatRule{}, fmt.Errorf("max repeats should be at least 1, got 0")
+		}
+		return RepeatedBetween(minK, maxK), nil
+	}
+
+	k, err := strconv.Atoi(s)
+	if err != nil {
+		return RepeatRule{}, err
+	}
+	return RepeatedExactly(k), nil
+}
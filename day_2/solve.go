@@ -1,13 +1,49 @@
 package day2
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"iter"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+
+	"github.com/jibaru/advent-of-code-2025/numeric"
 )
 
+// ErrOverflow is returned by the int path when a sum does not fit in an
+// int. It is numeric.ErrOverflow, so either can be matched with errors.Is.
+var ErrOverflow = numeric.ErrOverflow
+
+type Options struct {
+	// Big parses and sums product IDs with math/big instead of int.
+	Big bool
+	// Rule overrides which IDs are invalid. Zero fields keep the part's
+	// default: base 10, exactly two repeats for part one and at least two
+	// for part two. Use ParseRepeatRule or Validate to reject a rule that
+	// was given explicitly instead of falling back to the default.
+	Rule RepeatRule
+}
+
+func (opts Options) rule(def RepeatRule) RepeatRule {
+	rule := opts.Rule
+	if rule.MinRepeats == 0 {
+		rule.MinRepeats = def.MinRepeats
+		rule.MaxRepeats = def.MaxRepeats
+	}
+	if rule.Base == 0 {
+		rule.Base = def.Base
+	}
+	return rule
+}
+
 func Solve(part int, isTest bool) (any, error) {
+	return SolveWithOptions(part, isTest, Options{})
+}
+
+func SolveWithOptions(part int, isTest bool, opts Options) (any, error) {
 	f := "day_2/input.txt"
 	if isTest {
 		f = "day_2/input-test.txt"
@@ -20,119 +56,127 @@ func Solve(part int, isTest bool) (any, error) {
 
 	switch part {
 	case 1:
-		return partOne(string(body))
+		return partOne(string(body), opts)
 	case 2:
-		return partTwo(string(body))
+		return partTwo(string(body), opts)
 	}
 
 	return nil, fmt.Errorf("part should be only 1 or 2")
 }
 
-func partOne(data string) (any, error) {
-	idRanges, err := parseProductIDRanges(data)
-	if err != nil {
+func partOne(data string, opts Options) (any, error) {
+	return sumInvalidIDs(data, opts.rule(partOneRule), opts.Big)
+}
+
+func partTwo(data string, opts Options) (any, error) {
+	return sumInvalidIDs(data, opts.rule(partTwoRule), opts.Big)
+}
+
+func sumInvalidIDs(data string, rule RepeatRule, useBig bool) (any, error) {
+	if err := rule.Validate(); err != nil {
 		return nil, err
 	}
 
-	ans := 0
-	for _, idRange := range idRanges {
-		for _, invalidID := range idRange.PartOneInvalidIDs() {
-			ans += invalidID
+	if !useBig {
+		ans, err := sumIntInvalidIDs(data, rule)
+		if !isOverflow(err) {
+			return ans, err
 		}
 	}
 
-	return ans, nil
+	return sumBigInvalidIDs(data, rule)
 }
 
-func partTwo(data string) (any, error) {
+// sumIntInvalidIDs is the int fast path. It fails with ErrOverflow or
+// strconv.ErrRange when the input needs the big path.
+func sumIntInvalidIDs(data string, rule RepeatRule) (int, error) {
 	idRanges, err := parseProductIDRanges(data)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
 	ans := 0
 	for _, idRange := range idRanges {
-		for _, invalidID := range idRange.PartTwoInvalidIDs() {
-			ans += invalidID
+		for invalidID := range idRange.InvalidIDsSeq(rule) {
+			ans, err = numeric.Add(ans, invalidID)
+			if err != nil {
+				return 0, err
+			}
 		}
 	}
 
 	return ans, nil
 }
 
+func isOverflow(err error) bool {
+	return errors.Is(err, ErrOverflow) || errors.Is(err, strconv.ErrRange)
+}
+
 type ProductIDRange struct {
 	First int
 	Last  int
 }
 
 func (idRange *ProductIDRange) PartOneInvalidIDs() []int {
-	invalidIDs := []int{}
-	for id := idRange.First; id <= idRange.Last; id++ {
-		if repeatSequenceOfDigitsTwice(id) {
-			invalidIDs = append(invalidIDs, id)
-		}
-	}
-	return invalidIDs
+	return idRange.InvalidIDs(partOneRule)
 }
 
 func (idRange *ProductIDRange) PartTwoInvalidIDs() []int {
-	invalidIDs := []int{}
-	for id := idRange.First; id <= idRange.Last; id++ {
-		if repeatSequenceOfDigitsAtLeastTwice(id) {
-			invalidIDs = append(invalidIDs, id)
-		}
-	}
-	return invalidIDs
+	return idRange.InvalidIDs(partTwoRule)
 }
 
-func repeatSequenceOfDigitsTwice(number int) bool {
-	s := strconv.Itoa(number)
-	lenght := len(s)
-
-	if lenght%2 != 0 {
-		return false
-	}
-
-	halfLen := lenght / 2
-	return s[:halfLen] == s[halfLen:]
+// InvalidIDs returns the IDs in the range matched by rule.
+func (idRange *ProductIDRange) InvalidIDs(rule RepeatRule) []int {
+	return slices.Collect(idRange.InvalidIDsSeq(rule))
 }
 
-func repeatSequenceOfDigitsAtLeastTwice(number int) bool {
-	s := strconv.Itoa(number)
-	lenght := len(s)
-
-	for size := 1; size <= lenght/2; size++ {
-		if lenght%size != 0 {
-			continue
-		}
-		pattern := s[:size]
+func (idRange *ProductIDRange) PartOneInvalidIDsSeq() iter.Seq[int] {
+	return idRange.InvalidIDsSeq(partOneRule)
+}
 
-		repeated := strings.Repeat(pattern, lenght/size)
+func (idRange *ProductIDRange) PartTwoInvalidIDsSeq() iter.Seq[int] {
+	return idRange.InvalidIDsSeq(partTwoRule)
+}
 
-		if repeated == s {
-			return true
+// InvalidIDsSeq lazily yields the IDs in the range matched by rule, in
+// ascending order.
+func (idRange *ProductIDRange) InvalidIDsSeq(rule RepeatRule) iter.Seq[int] {
+	first, last := idRange.First, idRange.Last
+	return func(yield func(int) bool) {
+		for id := first; id <= last; id++ {
+			if rule.Match(id) && !yield(id) {
+				return
+			}
+			// avoid wrapping around when last is math.MaxInt
+			if id == last {
+				return
+			}
 		}
 	}
-
-	return false
 }
 
+// parseProductIDRanges parses and validates the comma separated ranges, then
+// returns them sorted and merged so that no ID is visited twice.
 func parseProductIDRanges(data string) ([]ProductIDRange, error) {
 	var ranges []ProductIDRange
-	for _, rangeStr := range strings.Split(data, ",") {
+	for i, rangeStr := range splitProductIDRanges(data) {
 		firstStr, lastStr, found := strings.Cut(rangeStr, "-")
 		if !found {
-			return nil, fmt.Errorf("invalid range: %v", rangeStr)
+			return nil, fmt.Errorf("range %d: invalid range: %v", i+1, rangeStr)
 		}
 
-		first, err := strconv.Atoi(firstStr)
+		first, err := strconv.Atoi(strings.TrimSpace(firstStr))
 		if err != nil {
-			return nil, fmt.Errorf("invalid first number in range %v: %v", rangeStr, err)
+			return nil, fmt.Errorf("range %d: invalid first number in range %v: %w", i+1, rangeStr, err)
 		}
 
-		last, err := strconv.Atoi(lastStr)
+		last, err := strconv.Atoi(strings.TrimSpace(lastStr))
 		if err != nil {
-			return nil, fmt.Errorf("invalid last number in range %v: %v", rangeStr, err)
+			return nil, fmt.Errorf("range %d: invalid last number in range %v: %w", i+1, rangeStr, err)
+		}
+
+		if last < first {
+			return nil, fmt.Errorf("range %d: last number is lower than first in range %v", i+1, rangeStr)
 		}
 
 		ranges = append(ranges, ProductIDRange{
@@ -141,5 +185,41 @@ func parseProductIDRanges(data string) ([]ProductIDRange, error) {
 		})
 	}
 
-	return ranges, nil
+	return normalizeProductIDRanges(ranges), nil
+}
+
+// splitProductIDRanges splits the input by commas, trimming whitespace and
+// newlines and dropping empty entries.
+func splitProductIDRanges(data string) []string {
+	var rangeStrs []string
+	for _, rangeStr := range strings.Split(data, ",") {
+		rangeStr = strings.TrimSpace(rangeStr)
+		if rangeStr == "" {
+			continue
+		}
+		rangeStrs = append(rangeStrs, rangeStr)
+	}
+	return rangeStrs
+}
+
+// normalizeProductIDRanges sorts the ranges and merges the ones that overlap
+// or are next to each other.
+func normalizeProductIDRanges(ranges []ProductIDRange) []ProductIDRange {
+	slices.SortFunc(ranges, func(a, b ProductIDRange) int {
+		return cmp.Compare(a.First, b.First)
+	})
+
+	var merged []ProductIDRange
+	for _, idRange := range ranges {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			if idRange.First <= last.Last || idRange.First-1 == last.Last {
+				last.Last = max(last.Last, idRange.Last)
+				continue
+			}
+		}
+		merged = append(merged, idRange)
+	}
+
+	return merged
 }
@@ -2,12 +2,32 @@ package day3
 
 import (
 	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
 	"os"
-	"strconv"
+	"slices"
 	"strings"
+
+	"github.com/jibaru/advent-of-code-2025/containers"
+	"github.com/jibaru/advent-of-code-2025/numeric"
 )
 
+type Options struct {
+	// Explain, when set, receives the batteries picked in every bank.
+	Explain io.Writer
+	// Verify checks every selection against brute force on small banks.
+	Verify bool
+	// K is the number of batteries turned on per bank in part two.
+	// Zero means 12.
+	K int
+}
+
 func Solve(part int, isTest bool) (any, error) {
+	return SolveWithOptions(part, isTest, Options{})
+}
+
+func SolveWithOptions(part int, isTest bool, opts Options) (any, error) {
 	f := "day_3/input.txt"
 	if isTest {
 		f = "day_3/input-test.txt"
@@ -20,162 +40,258 @@ func Solve(part int, isTest bool) (any, error) {
 
 	switch part {
 	case 1:
-		return partOne(string(body))
+		return partOne(string(body), opts)
 	case 2:
-		return partTwo(string(body))
+		return partTwo(string(body), opts)
 	}
 
 	return nil, fmt.Errorf("part should be only 1 or 2")
 }
 
-func partOne(data string) (any, error) {
+func partOne(data string, opts Options) (any, error) {
 	banks, err := parseBank(data)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := checkBankSizes(banks, 2); err != nil {
+		return nil, err
+	}
+
 	ans := 0
-	for _, bank := range banks {
-		ans += largestVoltage(bank)
+	for i, bank := range banks {
+		sel := selectLargestVoltageK(bank, 2)
+		if err := opts.inspect(i, bank, 2, sel); err != nil {
+			return nil, err
+		}
+		ans += sel.Value()
 	}
 
 	return ans, nil
 }
 
-func partTwo(data string) (any, error) {
+func partTwo(data string, opts Options) (any, error) {
 	banks, err := parseBank(data)
 	if err != nil {
 		return nil, err
 	}
 
-	ans := 0
-	for _, bank := range banks {
-		ans += largestVoltageK(bank, 12)
+	size := opts.K
+	if size == 0 {
+		size = 12
+	}
+	if size < 0 {
+		return nil, fmt.Errorf("number of batteries should be positive, got %d", size)
 	}
 
-	return ans, nil
+	if err := checkBankSizes(banks, size); err != nil {
+		return nil, err
+	}
+
+	// the total switches to math/big once a selection or the sum no longer fits
+	var total numeric.Total
+	for i, bank := range banks {
+		sel := selectLargestVoltageK(bank, size)
+		if err := opts.inspect(i, bank, size, sel); err != nil {
+			return nil, err
+		}
+		if len(sel.Digits) <= maxSafeDigits {
+			total.Add(sel.Value())
+		} else {
+			total.AddBig(sel.BigValue())
+		}
+	}
+
+	return total.Result(), nil
 }
 
 type Battery = int
 
 type Bank []Battery
 
+// parseBank reads one bank per line, skipping blank lines. Every other
+// character must be a digit.
 func parseBank(data string) ([]Bank, error) {
 	var banks []Bank
-	for _, line := range strings.Split(data, "\n") {
+	for row, line := range strings.Split(data, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
 		var bank Bank
-		for _, batteryStr := range line {
-			b, err := strconv.Atoi(string(batteryStr))
-			if err != nil {
-				return nil, err
+		col := 0
+		for _, batteryRune := range line {
+			col++
+			if batteryRune < '0' || batteryRune > '9' {
+				return nil, fmt.Errorf("line %d, column %d: invalid battery %q", row+1, col, batteryRune)
 			}
-			bank = append(bank, Battery(b))
+			bank = append(bank, Battery(batteryRune-'0'))
 		}
 		banks = append(banks, bank)
 	}
 	return banks, nil
 }
 
-func largestVoltage(bank Bank) int {
-	m1 := -1
-	idx := -1
-	for i := 0; i < len(bank)-1; i++ {
-		if bank[i] > m1 {
-			m1 = bank[i]
-			idx = i
+// checkBankSizes makes sure every bank has at least size batteries to turn on.
+func checkBankSizes(banks []Bank, size int) error {
+	for i, bank := range banks {
+		if len(bank) < size {
+			return fmt.Errorf("bank %d has %d batteries, cannot turn on %d", i+1, len(bank), size)
 		}
 	}
+	return nil
+}
+
+func largestVoltageK(bank Bank, size int) int {
+	return selectLargestVoltageK(bank, size).Value()
+}
+
+// Selection holds the batteries turned on in a bank, in bank order.
+type Selection struct {
+	Indices []int
+	Digits  Bank
+}
 
-	m2 := -1
-	for i := idx + 1; i < len(bank); i++ {
-		m2 = max(m2, bank[i])
+// Value returns the selection as an int. It overflows when there are more
+// than maxSafeDigits digits, use BigValue or Decimal for those.
+func (s Selection) Value() int {
+	return bankToInt(s.Digits)
+}
+
+func (s Selection) BigValue() *big.Int {
+	return bankToBig(s.Digits)
+}
+
+// Decimal returns the selected digits as a string, like "987".
+func (s Selection) Decimal() string {
+	var b strings.Builder
+	for _, d := range s.Digits {
+		b.WriteByte(byte('0' + d))
 	}
+	return b.String()
+}
 
-	return m1*10 + m2
+func (s Selection) String() string {
+	return fmt.Sprintf("%s from indices %v", s.Decimal(), s.Indices)
 }
 
-func largestVoltageK(bank Bank, size int) int {
+// maxSafeDigits is the largest number of decimal digits that always fits in an int.
+const maxSafeDigits = 18
+
+// selectLargestVoltageK picks the size batteries that form the largest
+// number, keeping track of where each of them is in the bank.
+func selectLargestVoltageK(bank Bank, size int) Selection {
 	numberOfBatteries := len(bank)
 	if size >= numberOfBatteries {
-		return bankToInt(bank)
+		indices := make([]int, numberOfBatteries)
+		for i := range indices {
+			indices[i] = i
+		}
+		return Selection{Indices: indices, Digits: append(Bank{}, bank...)}
 	}
 
 	toRemove := numberOfBatteries - size
-	stack := NewStack[Battery](numberOfBatteries)
+	stack := containers.NewStack[int](numberOfBatteries)
 
-	for _, curr := range bank {
-		for toRemove > 0 && !stack.IsEmpty() && stack.Top() < curr {
+	for i, curr := range bank {
+		for toRemove > 0 && !stack.IsEmpty() && bank[stack.Top()] < curr {
 			stack.Pop()
 			toRemove--
 		}
 
-		stack.Push(curr)
+		stack.Push(i)
 	}
 
 	stack.TrimLast(toRemove)
 
-	slice := stack.Slice()
-	return bankToInt(slice[:size])
+	indices := stack.Slice()[:size]
+	digits := make(Bank, size)
+	for i, idx := range indices {
+		digits[i] = bank[idx]
+	}
+
+	return Selection{Indices: indices, Digits: digits}
 }
 
-// bankToInt converts a bank to number, like {9,8,7} to 987
-func bankToInt(b Bank) int {
-	var result int = 0
-	for _, d := range b {
-		result = result*10 + int(d)
+// inspect prints and verifies the selection of a bank when the options ask for it.
+func (opts Options) inspect(index int, bank Bank, size int, sel Selection) error {
+	if opts.Verify && len(bank) <= maxBruteForceBatteries {
+		if err := verifySelection(bank, size, sel); err != nil {
+			return fmt.Errorf("bank %d: %w", index+1, err)
+		}
 	}
-	return result
-}
 
-type Stack[T any] struct {
-	data []T
-}
+	if opts.Explain != nil {
+		fmt.Fprintf(opts.Explain, "bank %d: %v\n", index+1, sel)
+	}
 
-func NewStack[T any](capacity int) *Stack[T] {
-	return &Stack[T]{data: make([]T, 0, capacity)}
+	return nil
 }
 
-func (s *Stack[T]) Push(v T) {
-	s.data = append(s.data, v)
-}
+// maxBruteForceBatteries limits verifySelection to banks with at most
+// 2^20 subsets.
+const maxBruteForceBatteries = 20
 
-func (s *Stack[T]) Pop() T {
-	var zero T
-	if len(s.data) == 0 {
-		return zero
+// verifySelection checks that sel is a valid pick of size batteries from bank
+// and that no other pick gives a larger number, by trying every subset.
+func verifySelection(bank Bank, size int, sel Selection) error {
+	if len(bank) > maxBruteForceBatteries {
+		return fmt.Errorf("bank has %d batteries, brute force supports up to %d", len(bank), maxBruteForceBatteries)
 	}
-	last := s.data[len(s.data)-1]
-	s.data = s.data[:len(s.data)-1]
-	return last
-}
 
-func (s *Stack[T]) Top() T {
-	var zero T
-	if len(s.data) == 0 {
-		return zero
+	size = min(size, len(bank))
+	if len(sel.Indices) != size || len(sel.Digits) != size {
+		return fmt.Errorf("selection has %d batteries, expected %d", len(sel.Indices), size)
 	}
-	return s.data[len(s.data)-1]
-}
 
-func (s *Stack[T]) Len() int {
-	return len(s.data)
-}
+	for i, idx := range sel.Indices {
+		if idx < 0 || idx >= len(bank) || (i > 0 && idx <= sel.Indices[i-1]) {
+			return fmt.Errorf("selection indices %v are not increasing positions in the bank", sel.Indices)
+		}
+		if bank[idx] != sel.Digits[i] {
+			return fmt.Errorf("selection digit %d does not match battery %d at index %d", sel.Digits[i], bank[idx], idx)
+		}
+	}
 
-func (s *Stack[T]) IsEmpty() bool {
-	return len(s.data) == 0
+	var best, digits Bank
+	for mask := 0; mask < 1<<len(bank); mask++ {
+		if bits.OnesCount(uint(mask)) != size {
+			continue
+		}
+		digits = digits[:0]
+		for i, b := range bank {
+			if mask&(1<<i) != 0 {
+				digits = append(digits, b)
+			}
+		}
+		if best == nil || slices.Compare(digits, best) > 0 {
+			best = append(best[:0], digits...)
+		}
+	}
+
+	if slices.Compare(sel.Digits, best) != 0 {
+		return fmt.Errorf("selection %s is not the largest voltage %s", sel.Decimal(), Selection{Digits: best}.Decimal())
+	}
+
+	return nil
 }
 
-// TrimLast removes the last n elements
-func (s *Stack[T]) TrimLast(n int) {
-	if n <= 0 || n > len(s.data) {
-		return
+// bankToInt converts a bank to number, like {9,8,7} to 987
+func bankToInt(b Bank) int {
+	var result int = 0
+	for _, d := range b {
+		result = result*10 + int(d)
 	}
-	s.data = s.data[:len(s.data)-n]
+	return result
 }
 
-// Slice returns a copy of the internal slice
-func (s *Stack[T]) Slice() []T {
-	out := make([]T, len(s.data))
-	copy(out, s.data)
-	return out
+func bankToBig(b Bank) *big.Int {
+	result := new(big.Int)
+	ten := big.NewInt(10)
+	for _, d := range b {
+		result.Mul(result, ten)
+		result.Add(result, big.NewInt(int64(d)))
+	}
+	return result
 }
@@ -0,0 +1,195 @@
+package day4
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"iter"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jibaru/advent-of-code-2025/grid"
+)
+
+// Timeline records how part two empties the grid: the starting grid and the
+// rolls removed in every round.
+type Timeline struct {
+	Start  Grid
+	Rounds [][]grid.Pos
+}
+
+type AnimationFormat string
+
+const (
+	AnimationANSI AnimationFormat = "ansi"
+	AnimationText AnimationFormat = "text"
+	AnimationGIF  AnimationFormat = "gif"
+)
+
+// removedCell marks, in a frame, the rolls removed in that frame's round.
+const removedCell Cell = 'x'
+
+const (
+	frameDelay = 100 * time.Millisecond
+	gifScale   = 4
+)
+
+// Removed returns how many rolls were removed in total.
+func (t Timeline) Removed() int {
+	removed := 0
+	for _, round := range t.Rounds {
+		removed += len(round)
+	}
+	return removed
+}
+
+// frames yields the starting grid, then the grid of every round with the
+// rolls removed in it marked as removedCell, then the final grid. The same
+// grid is reused between frames.
+func (t Timeline) frames() iter.Seq2[int, Grid] {
+	return func(yield func(int, Grid) bool) {
+		frame := Grid{t.Start.Clone()}
+		if !yield(0, frame) {
+			return
+		}
+
+		var previous []grid.Pos
+		for i, round := range t.Rounds {
+			for _, p := range previous {
+				frame.Set(p, '.')
+			}
+			for _, p := range round {
+				frame.Set(p, removedCell)
+			}
+			if !yield(i+1, frame) {
+				return
+			}
+			previous = round
+		}
+
+		if len(previous) == 0 {
+			return
+		}
+		for _, p := range previous {
+			frame.Set(p, '.')
+		}
+		yield(len(t.Rounds)+1, frame)
+	}
+}
+
+// Frames returns every frame as text, one row per line.
+func (t Timeline) Frames() []string {
+	var out []string
+	for _, frame := range t.frames() {
+		out = append(out, frame.String())
+	}
+	return out
+}
+
+func (t Timeline) Write(w io.Writer, format AnimationFormat) error {
+	switch format {
+	case AnimationANSI, "":
+		delay := frameDelay
+		if !isTerminal(w) {
+			// nobody is watching a file fill up
+			delay = 0
+		}
+		return t.WriteANSI(w, delay)
+	case AnimationText:
+		return t.WriteText(w)
+	case AnimationGIF:
+		return t.WriteGIF(w, gifScale, frameDelay)
+	}
+	return fmt.Errorf("unknown animation format: %q", format)
+}
+
+// isTerminal reports whether w is a terminal rather than a file or a pipe.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// WriteText writes every frame preceded by a header with its round.
+func (t Timeline) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for i, frame := range t.frames() {
+		fmt.Fprintf(bw, "round %d\n%v\n", i, frame)
+	}
+	return bw.Flush()
+}
+
+// WriteANSI plays the frames in a terminal, waiting delay between them.
+// Rolls removed in the current round are shown in red. Write only waits
+// when w is a terminal.
+func (t Timeline) WriteANSI(w io.Writer, delay time.Duration) error {
+	for i, frame := range t.frames() {
+		if i > 0 && delay > 0 {
+			time.Sleep(delay)
+		}
+
+		var b strings.Builder
+		// move to the top left corner and clear the screen
+		b.WriteString("\x1b[H\x1b[2J")
+		fmt.Fprintf(&b, "round %d\n", i)
+		b.WriteString(frame.Format(func(c Cell) string {
+			if c == removedCell {
+				return "\x1b[31m@\x1b[0m"
+			}
+			return string(c)
+		}))
+
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var gifPalette = color.Palette{
+	color.White,
+	color.RGBA{0x8b, 0x5a, 0x2b, 0xff}, // roll
+	color.RGBA{0xd0, 0x20, 0x20, 0xff}, // removed this round
+}
+
+// WriteGIF encodes the frames as an animated GIF where every cell is a
+// scale x scale square.
+func (t Timeline) WriteGIF(w io.Writer, scale int, delay time.Duration) error {
+	if scale < 1 {
+		return fmt.Errorf("gif scale should be positive, got %d", scale)
+	}
+
+	bounds := image.Rect(0, 0, t.Start.Cols()*scale, t.Start.Rows()*scale)
+	anim := &gif.GIF{}
+	for _, frame := range t.frames() {
+		img := image.NewPaletted(bounds, gifPalette)
+		for p, cell := range frame.All() {
+			var idx uint8
+			switch cell {
+			case '@':
+				idx = 1
+			case removedCell:
+				idx = 2
+			default:
+				continue
+			}
+			for y := p.Row * scale; y < (p.Row+1)*scale; y++ {
+				for x := p.Col * scale; x < (p.Col+1)*scale; x++ {
+					img.SetColorIndex(x, y, idx)
+				}
+			}
+		}
+		anim.Image = append(anim.Image, img)
+		// gif delays are in hundredths of a second
+		anim.Delay = append(anim.Delay, int(delay/(10*time.Millisecond)))
+	}
+
+	return gif.EncodeAll(w, anim)
+}
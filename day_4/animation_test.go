@@ -0,0 +1,31 @@
+package day4
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+const animationInput = "@@@\n@@@\n@@@\n"
+
+func TestWriteANSIToBufferDoesNotWait(t *testing.T) {
+	var buf bytes.Buffer
+	start := time.Now()
+	if _, err := partTwo(animationInput, Options{Animation: &buf}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed >= frameDelay {
+		t.Fatalf("writing to a buffer took %v, want no frame delay", elapsed)
+	}
+	if !strings.Contains(buf.String(), "round 1\n") {
+		t.Fatalf("animation has no rounds:\n%s", buf.String())
+	}
+}
+
+func TestPartOneRejectsAnimation(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := partOne(animationInput, Options{Animation: &buf}); err == nil {
+		t.Fatal("part one accepted an animation")
+	}
+}
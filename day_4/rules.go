@@ -0,0 +1,183 @@
+package day4
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/jibaru/advent-of-code-2025/grid"
+)
+
+type EdgeMode int
+
+const (
+	// Bounded ignores the neighbours that fall outside the grid.
+	Bounded EdgeMode = iota
+	// Wrap makes the grid toroidal: neighbours past an edge come from the
+	// opposite one.
+	Wrap
+)
+
+var (
+	MooreNeighbourhood      = grid.Directions8
+	VonNeumannNeighbourhood = grid.Directions4
+)
+
+// Rules describe what the input looks like and when a roll is accessible:
+// a roll is accessible when fewer than Threshold of the cells at the
+// Neighbourhood offsets hold rolls.
+type Rules struct {
+	Neighbourhood []grid.Pos
+	Threshold     int
+	// Occupied and Empty are the input glyphs. Parsed grids always use
+	// '@' and '.' whatever the glyphs are.
+	Occupied rune
+	Empty    rune
+	Edges    EdgeMode
+}
+
+// DefaultRules are the rules of the puzzle: fewer than 4 of the 8
+// surrounding cells are rolls, without wrapping around.
+func DefaultRules() Rules {
+	return Rules{
+		Neighbourhood: MooreNeighbourhood,
+		Threshold:     4,
+		Occupied:      '@',
+		Empty:         '.',
+		Edges:         Bounded,
+	}
+}
+
+// maxNeighbourhood is the largest number of offsets in a neighbourhood, so
+// that neighbour counts fit in a byte.
+const maxNeighbourhood = 255
+
+func (r Rules) Validate() error {
+	if len(r.Neighbourhood) > maxNeighbourhood {
+		return fmt.Errorf("neighbourhood has %d offsets, up to %d are supported", len(r.Neighbourhood), maxNeighbourhood)
+	}
+	if r.Occupied == r.Empty {
+		return fmt.Errorf("occupied and empty glyphs should be different, both are %q", r.Occupied)
+	}
+	if r.Edges != Bounded && r.Edges != Wrap {
+		return fmt.Errorf("unknown edge mode: %d", r.Edges)
+	}
+	return nil
+}
+
+func (r Rules) parseGrid(data string) (Grid, error) {
+	g, err := grid.Parse(data, func(char rune) (Cell, error) {
+		switch char {
+		case r.Occupied:
+			return '@', nil
+		case r.Empty:
+			return '.', nil
+		}
+		return 0, fmt.Errorf("invalid cell character: %q", char)
+	})
+	if err != nil {
+		return Grid{}, err
+	}
+
+	return Grid{g}, nil
+}
+
+func (r Rules) IsAccessible(s Storage, p grid.Pos) bool {
+	return r.adjacentRolls(s, p) < r.Threshold
+}
+
+func (r Rules) adjacentRolls(s Storage, p grid.Pos) int {
+	count := 0
+	for n := range r.neighbours(s, p) {
+		if s.Occupied(n) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// adjacentCounts yields every roll with how many rolls are in its
+// neighbourhood, counting a word at a time when the storage allows it.
+func (r Rules) adjacentCounts(s Storage) iter.Seq2[grid.Pos, int] {
+	if b, ok := s.(*bitGrid); ok && r.isMooreBounded() {
+		return b.adjacentCounts()
+	}
+
+	return func(yield func(grid.Pos, int) bool) {
+		for p := range s.Rolls() {
+			if !yield(p, r.adjacentRolls(s, p)) {
+				return
+			}
+		}
+	}
+}
+
+// countAccessible counts the rolls that are accessible right now.
+func (r Rules) countAccessible(s Storage) int {
+	if b, ok := s.(*bitGrid); ok && r.isMooreBounded() {
+		return b.countBelow(r.Threshold)
+	}
+
+	ans := 0
+	for _, count := range r.adjacentCounts(s) {
+		if count < r.Threshold {
+			ans++
+		}
+	}
+	return ans
+}
+
+// isMooreBounded reports whether the rules use the 8 surrounding cells
+// without wrapping, which bitGrid can count a word at a time.
+func (r Rules) isMooreBounded() bool {
+	if r.Edges != Bounded || len(r.Neighbourhood) != len(MooreNeighbourhood) {
+		return false
+	}
+	for _, offset := range MooreNeighbourhood {
+		if !slices.Contains(r.Neighbourhood, offset) {
+			return false
+		}
+	}
+	return true
+}
+
+// neighbours yields the cells in the neighbourhood of p.
+func (r Rules) neighbours(s Storage, p grid.Pos) iter.Seq[grid.Pos] {
+	return r.around(s, p, 1)
+}
+
+// dependents yields the cells that have p in their neighbourhood, which are
+// the ones to re-examine when the roll at p is removed. It only differs from
+// neighbours for asymmetric neighbourhoods.
+func (r Rules) dependents(s Storage, p grid.Pos) iter.Seq[grid.Pos] {
+	return r.around(s, p, -1)
+}
+
+// around yields p plus every offset multiplied by sign. A cell shows up once
+// per offset that reaches it, which can happen more than once when wrapping
+// around small grids.
+func (r Rules) around(s Storage, p grid.Pos, sign int) iter.Seq[grid.Pos] {
+	return func(yield func(grid.Pos) bool) {
+		for _, offset := range r.Neighbourhood {
+			n := grid.Pos{Row: p.Row + sign*offset.Row, Col: p.Col + sign*offset.Col}
+			if r.Edges == Wrap {
+				n.Row = wrap(n.Row, s.Rows())
+				n.Col = wrap(n.Col, s.Cols())
+			}
+			if !s.InBounds(n) {
+				continue
+			}
+			if !yield(n) {
+				return
+			}
+		}
+	}
+}
+
+func wrap(i, n int) int {
+	if n == 0 {
+		return i
+	}
+	return ((i % n) + n) % n
+}
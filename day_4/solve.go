@@ -2,11 +2,36 @@ package day4
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"strings"
+
+	"github.com/jibaru/advent-of-code-2025/grid"
 )
 
+type Options struct {
+	// Animation, when set, receives the removal rounds of part two encoded
+	// as AnimationFormat.
+	Animation       io.Writer
+	AnimationFormat AnimationFormat
+	// Rules replace the puzzle rules when set.
+	Rules *Rules
+	// Backend forces how the grid is stored. By default it depends on the
+	// size and density of the grid.
+	Backend Backend
+}
+
+func (opts Options) rules() (Rules, error) {
+	if opts.Rules == nil {
+		return DefaultRules(), nil
+	}
+	return *opts.Rules, opts.Rules.Validate()
+}
+
 func Solve(part int, isTest bool) (any, error) {
+	return SolveWithOptions(part, isTest, Options{})
+}
+
+func SolveWithOptions(part int, isTest bool, opts Options) (any, error) {
 	f := "day_4/input.txt"
 	if isTest {
 		f = "day_4/input-test.txt"
@@ -19,143 +44,121 @@ func Solve(part int, isTest bool) (any, error) {
 
 	switch part {
 	case 1:
-		return partOne(string(body))
+		return partOne(string(body), opts)
 	case 2:
-		return partTwo(string(body))
+		return partTwo(string(body), opts)
 	}
 
 	return nil, fmt.Errorf("part should be only 1 or 2")
 }
 
-func partOne(data string) (any, error) {
-	grid, err := parseGrid(data)
+func partOne(data string, opts Options) (any, error) {
+	if opts.Animation != nil {
+		return nil, fmt.Errorf("animations are only available for part 2")
+	}
+
+	rules, err := opts.rules()
 	if err != nil {
 		return nil, err
 	}
 
-	ans := 0
-	for r := 0; r < len(grid); r++ {
-		for c := 0; c < len(grid[r]); c++ {
-			if grid[r][c] == '@' {
-				if grid.IsAccesibleAt(r, c) {
-					ans++
-				}
-			}
-		}
+	s, err := rules.parseStorage(data, opts.Backend)
+	if err != nil {
+		return nil, err
 	}
 
-	return ans, nil
+	return rules.countAccessible(s), nil
 }
 
-func partTwo(data string) (any, error) {
-	grid, err := parseGrid(data)
+func partTwo(data string, opts Options) (any, error) {
+	rules, err := opts.rules()
 	if err != nil {
 		return nil, err
 	}
 
-	type Pos struct {
-		r, c int
-	}
-
-	needsCheck := true
-	ans := 0
-	for needsCheck {
-		positions := []Pos{}
-		for r := range grid {
-			for c := 0; c < len(grid[r]); c++ {
-				if grid[r][c] == '@' {
-					if grid.IsAccesibleAt(r, c) {
-						positions = append(positions, Pos{
-							r: r,
-							c: c,
-						})
-					}
-				}
-			}
+	if opts.Animation == nil {
+		s, err := rules.parseStorage(data, opts.Backend)
+		if err != nil {
+			return nil, err
 		}
-		if len(positions) > 0 {
-			ans += len(positions)
-			needsCheck = true
 
-			for _, pos := range positions {
-				grid.ReplaceAt(pos.r, pos.c, '.')
-			}
-		} else {
-			needsCheck = false
-		}
+		return peelRolls(s, rules, nil), nil
 	}
 
-	return ans, nil
-}
+	// the animation needs every cell, so it always uses a Grid
+	g, err := rules.parseGrid(data)
+	if err != nil {
+		return nil, err
+	}
 
-type Cell rune
-type Row []Cell
-type Grid []Row
+	timeline := Timeline{Start: Grid{g.Clone()}}
+	peelRolls(g, rules, func(round []grid.Pos) {
+		timeline.Rounds = append(timeline.Rounds, round)
+	})
 
-func (g Grid) At(r, c int) (Cell, error) {
-	if r < 0 || r >= len(g) {
-		return 0, fmt.Errorf("row index out of bounds: %d", r)
-	}
-	if c < 0 || c >= len(g[r]) {
-		return 0, fmt.Errorf("column index out of bounds: %d", c)
+	if err := timeline.Write(opts.Animation, opts.AnimationFormat); err != nil {
+		return nil, err
 	}
-	return g[r][c], nil
-}
 
-func (g Grid) IsAccesibleAt(r, c int) bool {
-	return totalRollOfPapersAdjacent(g, r, c) < 4
+	return timeline.Removed(), nil
 }
 
-func (g Grid) ReplaceAt(r, c int, val Cell) error {
-	if r < 0 || r >= len(g) {
-		return fmt.Errorf("row index out of bounds: %d", r)
-	}
-	if c < 0 || c >= len(g[r]) {
-		return fmt.Errorf("column index out of bounds: %d", c)
+// peelRolls removes accessible rolls round by round until none is left,
+// passing the rolls removed in every round to onRound when it is set, and
+// returns how many were removed. Instead of rescanning the grid every round,
+// it keeps how many rolls surround every roll and only re-examines the
+// neighbours of the rolls removed in the previous round.
+func peelRolls(s Storage, rules Rules, onRound func([]grid.Pos)) int {
+	adjacent := newRollCounts(s, len(rules.Neighbourhood))
+
+	var round []grid.Pos
+	for p, count := range rules.adjacentCounts(s) {
+		adjacent.set(p, count)
+		if count < rules.Threshold {
+			round = append(round, p)
+		}
 	}
-	g[r][c] = val
-	return nil
-}
-
-func parseGrid(data string) (Grid, error) {
-	var grid []Row
 
-	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
-		if len(strings.TrimSpace(line)) == 0 {
-			continue
+	removed := 0
+	for len(round) > 0 {
+		for _, p := range round {
+			s.Remove(p)
+		}
+		removed += len(round)
+		if onRound != nil {
+			onRound(round)
 		}
 
-		var row Row
-		for _, char := range line {
-			if char != '.' && char != '@' {
-				return nil, fmt.Errorf("invalid cell character: %q", char)
+		var next []grid.Pos
+		for _, p := range round {
+			for n := range rules.dependents(s, p) {
+				if !s.Occupied(n) {
+					continue
+				}
+				count := adjacent.get(n)
+				adjacent.set(n, count-1)
+				// a roll becomes accessible only once, when it drops below the limit
+				if count-1 == rules.Threshold-1 {
+					next = append(next, n)
+				}
 			}
-			row = append(row, Cell(char))
 		}
-		grid = append(grid, row)
+		round = next
 	}
 
-	return grid, nil
+	return removed
 }
 
-func totalRollOfPapersAdjacent(grid Grid, r, c int) int {
-	directions := [][2]int{
-		{-1, -1}, {-1, 0}, {-1, 1},
-		{0, -1}, {0, 1},
-		{1, -1}, {1, 0}, {1, 1},
-	}
+type Cell rune
 
-	count := 0
-	for _, dir := range directions {
-		nr, nc := r+dir[0], c+dir[1]
-		cell, err := grid.At(nr, nc)
-		if err != nil {
-			continue
-		}
-		if cell == '@' {
-			count++
-		}
-	}
+func (c Cell) String() string {
+	return string(c)
+}
+
+type Grid struct {
+	grid.Grid[Cell]
+}
 
-	return count
+func (g Grid) IsAccesibleAt(p grid.Pos) bool {
+	return DefaultRules().IsAccessible(g, p)
 }
@@ -0,0 +1,439 @@
+package day4
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+	"slices"
+	"strings"
+
+	"github.com/jibaru/advent-of-code-2025/grid"
+)
+
+// Storage holds the rolls of a grid. Grid keeps every cell as a rune,
+// bitGrid keeps one bit per cell and sparseGrid only the positions of the
+// rolls.
+type Storage interface {
+	Rows() int
+	Cols() int
+	InBounds(p grid.Pos) bool
+	Occupied(p grid.Pos) bool
+	Remove(p grid.Pos)
+	// Rolls yields the position of every roll.
+	Rolls() iter.Seq[grid.Pos]
+}
+
+type Backend int
+
+const (
+	// AutoBackend picks the storage from the size and density of the grid.
+	AutoBackend Backend = iota
+	DenseBackend
+	BitBackend
+	SparseBackend
+)
+
+const (
+	// grids up to denseMaxCells cells are kept as a plain Grid
+	denseMaxCells = 1 << 20
+	// bigger grids with fewer rolls than this fraction of cells are kept sparse
+	sparseMaxDensity = 0.05
+)
+
+func chooseBackend(rows, cols, rolls int) Backend {
+	cells := rows * cols
+	switch {
+	case cells <= denseMaxCells:
+		return DenseBackend
+	case float64(rolls) < sparseMaxDensity*float64(cells):
+		return SparseBackend
+	}
+	return BitBackend
+}
+
+// parseStorage parses data into the given backend, picking one when it is
+// AutoBackend.
+func (r Rules) parseStorage(data string, backend Backend) (Storage, error) {
+	if backend != DenseBackend {
+		rolls := 0
+		rows, cols, err := r.scanRolls(data, func(grid.Pos) { rolls++ })
+		if err != nil {
+			return nil, err
+		}
+
+		switch backend {
+		case AutoBackend:
+			backend = chooseBackend(rows, cols, rolls)
+		case BitBackend, SparseBackend:
+		default:
+			return nil, fmt.Errorf("unknown backend: %d", backend)
+		}
+
+		switch backend {
+		case BitBackend:
+			b := newBitGrid(rows, cols)
+			_, _, err = r.scanRolls(data, b.set)
+			return b, err
+		case SparseBackend:
+			s := newSparseGrid(rows, cols)
+			_, _, err = r.scanRolls(data, s.add)
+			return s, err
+		}
+	}
+
+	g, err := r.parseGrid(data)
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// scanRolls checks data like parseGrid does, without keeping the cells:
+// every roll is passed to onRoll. It returns the size of the grid.
+func (r Rules) scanRolls(data string, onRoll func(grid.Pos)) (int, int, error) {
+	rows, cols := 0, 0
+	lineNum := 0
+	for line := range strings.SplitSeq(data, "\n") {
+		lineNum++
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		col := 0
+		for _, char := range line {
+			switch char {
+			case r.Occupied:
+				onRoll(grid.Pos{Row: rows, Col: col})
+			case r.Empty:
+			default:
+				return 0, 0, fmt.Errorf("line %d, column %d: invalid cell character: %q", lineNum, col+1, char)
+			}
+			col++
+		}
+
+		if rows > 0 && col != cols {
+			return 0, 0, fmt.Errorf("line %d has %d cells, expected %d", lineNum, col, cols)
+		}
+		cols = col
+		rows++
+	}
+
+	return rows, cols, nil
+}
+
+func (g Grid) Occupied(p grid.Pos) bool {
+	cell, _ := g.At(p)
+	return cell == '@'
+}
+
+func (g Grid) Remove(p grid.Pos) {
+	g.Set(p, '.')
+}
+
+func (g Grid) Rolls() iter.Seq[grid.Pos] {
+	return g.FindAll('@')
+}
+
+// bitGrid stores one bit per cell, 64 cells per word. Every row starts on a
+// new word and the bits past the last column are always zero.
+type bitGrid struct {
+	rows   int
+	cols   int
+	stride int
+	words  []uint64
+}
+
+func newBitGrid(rows, cols int) *bitGrid {
+	stride := (cols + 63) / 64
+	return &bitGrid{
+		rows:   rows,
+		cols:   cols,
+		stride: stride,
+		words:  make([]uint64, rows*stride),
+	}
+}
+
+func (b *bitGrid) Rows() int {
+	return b.rows
+}
+
+func (b *bitGrid) Cols() int {
+	return b.cols
+}
+
+func (b *bitGrid) InBounds(p grid.Pos) bool {
+	return p.Row >= 0 && p.Row < b.rows && p.Col >= 0 && p.Col < b.cols
+}
+
+func (b *bitGrid) Occupied(p grid.Pos) bool {
+	if !b.InBounds(p) {
+		return false
+	}
+	return b.words[p.Row*b.stride+p.Col/64]&(1<<(p.Col%64)) != 0
+}
+
+func (b *bitGrid) Remove(p grid.Pos) {
+	if b.InBounds(p) {
+		b.words[p.Row*b.stride+p.Col/64] &^= 1 << (p.Col % 64)
+	}
+}
+
+func (b *bitGrid) set(p grid.Pos) {
+	b.words[p.Row*b.stride+p.Col/64] |= 1 << (p.Col % 64)
+}
+
+func (b *bitGrid) Rolls() iter.Seq[grid.Pos] {
+	return func(yield func(grid.Pos) bool) {
+		for r := range b.rows {
+			for w := range b.stride {
+				word := b.words[r*b.stride+w]
+				for word != 0 {
+					i := bits.TrailingZeros64(word)
+					if !yield(grid.Pos{Row: r, Col: w*64 + i}) {
+						return
+					}
+					word &= word - 1
+				}
+			}
+		}
+	}
+}
+
+// word returns word w of row r, or 0 outside the grid.
+func (b *bitGrid) word(r, w int) uint64 {
+	if r < 0 || r >= b.rows || w < 0 || w >= b.stride {
+		return 0
+	}
+	return b.words[r*b.stride+w]
+}
+
+// neighbourPlanes counts, for the 64 cells of word w in row r, how many of
+// their 8 surrounding cells hold rolls. The counts are bit-sliced: bit i of
+// planes[k] is bit k of the count of the cell at bit i.
+func (b *bitGrid) neighbourPlanes(r, w int) [4]uint64 {
+	var planes [4]uint64
+	add := func(x uint64) {
+		for k := range planes {
+			carry := planes[k] & x
+			planes[k] ^= x
+			x = carry
+		}
+	}
+
+	for dr := -1; dr <= 1; dr++ {
+		prev, cur, next := b.word(r+dr, w-1), b.word(r+dr, w), b.word(r+dr, w+1)
+		// line up the cells at columns c-1 and c+1 with column c
+		add(cur<<1 | prev>>63)
+		add(cur>>1 | next<<63)
+		if dr != 0 {
+			add(cur)
+		}
+	}
+
+	return planes
+}
+
+// adjacentCounts yields every roll with how many of its 8 surrounding cells
+// hold rolls.
+func (b *bitGrid) adjacentCounts() iter.Seq2[grid.Pos, int] {
+	return func(yield func(grid.Pos, int) bool) {
+		for r := range b.rows {
+			for w := range b.stride {
+				occupied := b.words[r*b.stride+w]
+				if occupied == 0 {
+					continue
+				}
+				planes := b.neighbourPlanes(r, w)
+				for occupied != 0 {
+					i := bits.TrailingZeros64(occupied)
+					count := 0
+					for k, plane := range planes {
+						count |= int(plane>>i&1) << k
+					}
+					if !yield(grid.Pos{Row: r, Col: w*64 + i}, count) {
+						return
+					}
+					occupied &= occupied - 1
+				}
+			}
+		}
+	}
+}
+
+// countBelow counts the rolls with fewer than threshold surrounding rolls,
+// comparing 64 cells at a time.
+func (b *bitGrid) countBelow(threshold int) int {
+	total := 0
+	for r := range b.rows {
+		for w := range b.stride {
+			occupied := b.words[r*b.stride+w]
+			if occupied == 0 {
+				continue
+			}
+			planes := b.neighbourPlanes(r, w)
+
+			var below uint64
+			// counts go from 0 to 8
+			for v := range min(threshold, 9) {
+				equal := ^uint64(0)
+				for k, plane := range planes {
+					if v>>k&1 == 1 {
+						equal &= plane
+					} else {
+						equal &^= plane
+					}
+				}
+				below |= equal
+			}
+
+			total += bits.OnesCount64(occupied & below)
+		}
+	}
+	return total
+}
+
+// sparseGrid only stores the positions of the rolls.
+type sparseGrid struct {
+	rows  int
+	cols  int
+	rolls map[grid.Pos]struct{}
+}
+
+func newSparseGrid(rows, cols int) *sparseGrid {
+	return &sparseGrid{
+		rows:  rows,
+		cols:  cols,
+		rolls: make(map[grid.Pos]struct{}),
+	}
+}
+
+func (s *sparseGrid) Rows() int {
+	return s.rows
+}
+
+func (s *sparseGrid) Cols() int {
+	return s.cols
+}
+
+func (s *sparseGrid) InBounds(p grid.Pos) bool {
+	return p.Row >= 0 && p.Row < s.rows && p.Col >= 0 && p.Col < s.cols
+}
+
+func (s *sparseGrid) Occupied(p grid.Pos) bool {
+	_, ok := s.rolls[p]
+	return ok
+}
+
+func (s *sparseGrid) Remove(p grid.Pos) {
+	delete(s.rolls, p)
+}
+
+func (s *sparseGrid) add(p grid.Pos) {
+	s.rolls[p] = struct{}{}
+}
+
+// Rolls yields the rolls in no particular order.
+func (s *sparseGrid) Rolls() iter.Seq[grid.Pos] {
+	return func(yield func(grid.Pos) bool) {
+		for p := range s.rolls {
+			if !yield(p) {
+				return
+			}
+		}
+	}
+}
+
+// rollCounts keeps how many rolls are in the neighbourhood of every roll.
+type rollCounts interface {
+	get(p grid.Pos) int
+	set(p grid.Pos, count int)
+}
+
+// newRollCounts picks where to keep counts of up to maxCount for the rolls
+// of s, following its backend.
+func newRollCounts(s Storage, maxCount int) rollCounts {
+	switch s := s.(type) {
+	case *sparseGrid:
+		return sparseCounts{}
+	case *bitGrid:
+		return newPackedCounts(s, maxCount)
+	}
+	return denseCounts{cols: s.Cols(), counts: make([]uint8, s.Rows()*s.Cols())}
+}
+
+// denseCounts fits every count in a byte, which is why neighbourhoods are
+// limited to maxNeighbourhood offsets.
+type denseCounts struct {
+	cols   int
+	counts []uint8
+}
+
+func (c denseCounts) get(p grid.Pos) int {
+	return int(c.counts[p.Row*c.cols+p.Col])
+}
+
+func (c denseCounts) set(p grid.Pos, count int) {
+	c.counts[p.Row*c.cols+p.Col] = uint8(count)
+}
+
+// packedCounts only keeps the counts of the rolls of a bitGrid, each one in
+// the fewest bits that fit maxCount, so it stays close to the grid's one bit
+// per cell. The slot of a roll is its rank among the rolls the grid had when
+// the counts were made, which later removals do not change.
+type packedCounts struct {
+	stride int
+	words  []uint64 // the rolls when the counts were made
+	before []int    // rolls in the words before each word
+	width  int      // bits per count: 1, 2, 4 or 8 so none straddles a word
+	counts []uint64
+}
+
+func newPackedCounts(b *bitGrid, maxCount int) *packedCounts {
+	width := 1
+	for width < bits.Len(uint(maxCount)) {
+		width *= 2
+	}
+
+	c := &packedCounts{
+		stride: b.stride,
+		words:  slices.Clone(b.words),
+		before: make([]int, len(b.words)),
+		width:  width,
+	}
+	rolls := 0
+	for i, w := range c.words {
+		c.before[i] = rolls
+		rolls += bits.OnesCount64(w)
+	}
+	c.counts = make([]uint64, (rolls*width+63)/64)
+	return c
+}
+
+// slot returns the word and shift of the count of the roll at p.
+func (c *packedCounts) slot(p grid.Pos) (int, int) {
+	w := p.Row*c.stride + p.Col/64
+	rank := c.before[w] + bits.OnesCount64(c.words[w]&(1<<(p.Col%64)-1))
+	return rank * c.width / 64, rank * c.width % 64
+}
+
+func (c *packedCounts) get(p grid.Pos) int {
+	i, shift := c.slot(p)
+	return int(c.counts[i] >> shift & (1<<c.width - 1))
+}
+
+func (c *packedCounts) set(p grid.Pos, count int) {
+	i, shift := c.slot(p)
+	mask := uint64(1<<c.width-1) << shift
+	c.counts[i] = c.counts[i]&^mask | uint64(count)<<shift&mask
+}
+
+type sparseCounts map[grid.Pos]uint8
+
+func (c sparseCounts) get(p grid.Pos) int {
+	return int(c[p])
+}
+
+func (c sparseCounts) set(p grid.Pos, count int) {
+	c[p] = uint8(count)
+}
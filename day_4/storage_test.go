@@ -0,0 +1,105 @@
+package day4
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/jibaru/advent-of-code-2025/grid"
+)
+
+func randomStorageInput(r *rand.Rand, rows, cols int, density float64) string {
+	var b strings.Builder
+	for range rows {
+		for range cols {
+			if r.Float64() < density {
+				b.WriteByte('@')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// squareNeighbourhood is every offset up to radius away, 8 bits of counts
+// for radius 7.
+func squareNeighbourhood(radius int) []grid.Pos {
+	var offsets []grid.Pos
+	for dr := -radius; dr <= radius; dr++ {
+		for dc := -radius; dc <= radius; dc++ {
+			if dr != 0 || dc != 0 {
+				offsets = append(offsets, grid.Pos{Row: dr, Col: dc})
+			}
+		}
+	}
+	return offsets
+}
+
+func TestPeelRollsBackendsAgree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	rulesList := []Rules{DefaultRules()}
+	vonNeumann := DefaultRules()
+	vonNeumann.Neighbourhood = VonNeumannNeighbourhood
+	vonNeumann.Threshold = 2
+	rulesList = append(rulesList, vonNeumann)
+	wide := DefaultRules()
+	wide.Neighbourhood = squareNeighbourhood(7)
+	wide.Threshold = 100
+	wide.Edges = Wrap
+	rulesList = append(rulesList, wide)
+
+	for it := range 200 {
+		rows, cols := 1+r.Intn(20), 1+r.Intn(150)
+		data := randomStorageInput(r, rows, cols, r.Float64())
+		rules := rulesList[it%len(rulesList)]
+
+		want := -1
+		for _, backend := range []Backend{DenseBackend, BitBackend, SparseBackend} {
+			s, err := rules.parseStorage(data, backend)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := peelRolls(s, rules, nil)
+			if want < 0 {
+				want = got
+			} else if got != want {
+				t.Fatalf("%dx%d grid, rules %d: backend %d removed %d rolls, dense removed %d",
+					rows, cols, it%len(rulesList), backend, got, want)
+			}
+		}
+	}
+}
+
+func TestPackedCountsUseRollsOnly(t *testing.T) {
+	s, err := DefaultRules().parseStorage(randomStorageInput(rand.New(rand.NewSource(2)), 64, 640, 0.1), BitBackend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := s.(*bitGrid)
+
+	rolls := 0
+	for range b.Rolls() {
+		rolls++
+	}
+	c := newRollCounts(b, len(MooreNeighbourhood)).(*packedCounts)
+	// 4 bits per roll for counts up to 8
+	if want := (rolls*4 + 63) / 64; len(c.counts) != want {
+		t.Fatalf("%d count words for %d rolls, want %d", len(c.counts), rolls, want)
+	}
+
+	i := 0
+	for p := range b.Rolls() {
+		c.set(p, i%9)
+		i++
+	}
+	i = 0
+	for p := range b.Rolls() {
+		if got := c.get(p); got != i%9 {
+			t.Fatalf("get(%v) = %d, want %d", p, got, i%9)
+		}
+		i++
+	}
+}
@@ -0,0 +1,39 @@
+package day5
+
+import "github.com/jibaru/advent-of-code-2025/intervals"
+
+// Inventory keeps the fresh ranges up to date as they change during the day,
+// so IDs can be checked without parsing the database again.
+type Inventory struct {
+	fresh *intervals.Tree
+}
+
+func NewInventory(ranges []Range) *Inventory {
+	return &Inventory{fresh: intervals.NewTree(ranges...)}
+}
+
+// LoadInventory builds an inventory from the ranges section of a database.
+func LoadInventory(data string) (*Inventory, error) {
+	ranges, _, err := parseIngredientDB(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewInventory(ranges), nil
+}
+
+func (inv *Inventory) AddFresh(r Range) {
+	inv.fresh.Insert(r)
+}
+
+func (inv *Inventory) RemoveFresh(r Range) {
+	inv.fresh.Remove(r)
+}
+
+func (inv *Inventory) IsFresh(id int) bool {
+	return inv.fresh.Contains(id)
+}
+
+// FreshCount returns how many IDs are fresh right now.
+func (inv *Inventory) FreshCount() (int, error) {
+	return inv.fresh.Total()
+}
@@ -0,0 +1,130 @@
+package day5
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jibaru/advent-of-code-2025/intervals"
+)
+
+type Status string
+
+const (
+	Fresh   Status = "fresh"
+	Spoiled Status = "spoiled"
+)
+
+// IDReport explains the status of an ingredient ID: Matches are the input
+// ranges that contain it, in input order, and Merged is the range that
+// covers it once the overlapping ranges are merged.
+type IDReport struct {
+	ID      int     `json:"id"`
+	Status  Status  `json:"status"`
+	Matches []Range `json:"matches"`
+	Merged  *Range  `json:"merged"`
+}
+
+type Report []IDReport
+
+type ReportFormat string
+
+const (
+	ReportText ReportFormat = "text"
+	ReportCSV  ReportFormat = "csv"
+	ReportJSON ReportFormat = "json"
+)
+
+// buildReport explains every id. The merged ranges include the adjacent
+// ones only when mergeAdjacent is true.
+func buildReport(ranges []Range, ids IDList, mergeAdjacent bool) Report {
+	merged := intervals.Merge(ranges, mergeAdjacent)
+
+	report := make(Report, 0, len(ids))
+	for _, id := range ids {
+		r := IDReport{ID: id, Status: Spoiled, Matches: []Range{}}
+		for _, rng := range ranges {
+			if rng.Inside(id) {
+				r.Matches = append(r.Matches, rng)
+			}
+		}
+		i := sort.Search(len(merged), func(i int) bool {
+			return merged[i].To >= id
+		})
+		if i < len(merged) && merged[i].Inside(id) {
+			r.Status = Fresh
+			r.Merged = &merged[i]
+		}
+		report = append(report, r)
+	}
+
+	return report
+}
+
+func (report Report) Write(w io.Writer, format ReportFormat) error {
+	switch format {
+	case ReportText, "":
+		return report.WriteText(w)
+	case ReportCSV:
+		return report.WriteCSV(w)
+	case ReportJSON:
+		return report.WriteJSON(w)
+	}
+	return fmt.Errorf("unknown report format: %q", format)
+}
+
+// WriteText writes one line per ID, like "5: fresh, in 3-5 (merged 3-5)".
+func (report Report) WriteText(w io.Writer) error {
+	for _, r := range report {
+		line := fmt.Sprintf("%d: %s", r.ID, r.Status)
+		if r.Merged != nil {
+			line += fmt.Sprintf(", in %s (merged %v)", joinRanges(r.Matches, ", "), *r.Merged)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes a header and one record per ID. The matching ranges are
+// joined with ";" and the merged range columns are empty for spoiled IDs.
+func (report Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"id", "status", "matches", "merged_from", "merged_to"}); err != nil {
+		return err
+	}
+
+	for _, r := range report {
+		var mergedFrom, mergedTo string
+		if r.Merged != nil {
+			mergedFrom = strconv.Itoa(r.Merged.From)
+			mergedTo = strconv.Itoa(r.Merged.To)
+		}
+		record := []string{strconv.Itoa(r.ID), string(r.Status), joinRanges(r.Matches, ";"), mergedFrom, mergedTo}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func (report Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func joinRanges(ranges []Range, sep string) string {
+	parts := make([]string, len(ranges))
+	for i, r := range ranges {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, sep)
+}
@@ -2,13 +2,31 @@ package day5
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"sort"
+	"slices"
 	"strconv"
 	"strings"
+
+	"github.com/jibaru/advent-of-code-2025/intervals"
 )
 
+type Options struct {
+	// Report, when set, receives why every ingredient ID of part one is
+	// fresh or spoiled, encoded as ReportFormat.
+	Report       io.Writer
+	ReportFormat ReportFormat
+	// SeparateAdjacent keeps ranges like 3-5 and 6-8 apart when the report
+	// merges the ranges. They are merged by default. It only changes the
+	// report: which IDs are fresh, and how many, is the same either way.
+	SeparateAdjacent bool
+}
+
 func Solve(part int, isTest bool) (any, error) {
+	return SolveWithOptions(part, isTest, Options{})
+}
+
+func SolveWithOptions(part int, isTest bool, opts Options) (any, error) {
 	f := "day_5/input.txt"
 	if isTest {
 		f = "day_5/input-test.txt"
@@ -21,7 +39,7 @@ func Solve(part int, isTest bool) (any, error) {
 
 	switch part {
 	case 1:
-		return partOne(string(body))
+		return partOne(string(body), opts)
 	case 2:
 		return partTwo(string(body))
 	}
@@ -29,23 +47,20 @@ func Solve(part int, isTest bool) (any, error) {
 	return nil, fmt.Errorf("part should be only 1 or 2")
 }
 
-func partOne(data string) (any, error) {
+func partOne(data string, opts Options) (any, error) {
 	ranges, ids, err := parseIngredientDB(data)
 	if err != nil {
 		return nil, err
 	}
 
-	ans := 0
-	for _, id := range ids {
-		for _, r := range ranges {
-			if r.Inside(id) {
-				ans++
-				break
-			}
+	if opts.Report != nil {
+		report := buildReport(ranges, ids, !opts.SeparateAdjacent)
+		if err := report.Write(opts.Report, opts.ReportFormat); err != nil {
+			return nil, err
 		}
 	}
 
-	return ans, nil
+	return countFresh(intervals.NewSet(ranges...), ids), nil
 }
 
 func partTwo(data string) (any, error) {
@@ -54,68 +69,47 @@ func partTwo(data string) (any, error) {
 		return nil, err
 	}
 
-	// Sort by start
-	sort.Slice(ranges, func(i, j int) bool {
-		return ranges[i].From < ranges[j].From
-	})
-
-	ans := 0
-	current := ranges[0]
-
-	for _, r := range ranges[1:] {
-		if current.Overlaps(r) {
-			current = current.Merge(r)
-			continue
-		}
-
-		ans += current.Size()
-		current = r
+	size, err := intervals.NewSet(ranges...).Size()
+	if err != nil {
+		return nil, err
 	}
 
-	// Add the last range
-	ans += current.Size()
-
-	return ans, nil
-}
-
-type Range struct {
-	From int
-	To   int
+	return size, nil
 }
 
-type IDList []int
-
-func (r Range) Inside(id int) bool {
-	return id >= r.From && id <= r.To
-}
+type Range = intervals.Range
 
-func (r Range) Overlaps(other Range) bool {
-	return r.From <= other.To && other.From <= r.To
-}
+// countFresh counts the ids inside the fresh ranges. Sorted ids are counted
+// with a single sweep, the rest with a binary search per id.
+func countFresh(fresh *intervals.Set, ids IDList) int {
+	if slices.IsSorted(ids) {
+		return fresh.CountSorted(ids)
+	}
 
-func (r Range) Merge(other Range) Range {
-	return Range{
-		From: min(r.From, other.From),
-		To:   max(r.To, other.To),
+	ans := 0
+	for _, id := range ids {
+		if fresh.Contains(id) {
+			ans++
+		}
 	}
+	return ans
 }
 
-func (r Range) Size() int {
-	return r.To - r.From + 1
-}
+type IDList []int
 
+// parseIngredientDB splits the ranges from the ids at the first blank line.
+// Either section can be empty, and without a blank line there are no ids.
 func parseIngredientDB(data string) ([]Range, IDList, error) {
-	sections := strings.SplitN(strings.TrimSpace(data), "\n\n", 2)
-	if len(sections) != 2 {
-		return nil, nil, fmt.Errorf("expected two sections: ranges and ids")
-	}
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.TrimRight(data, " \t\n")
+	rangesSection, idsSection, _ := strings.Cut(data, "\n\n")
 
-	ranges, err := parseRangesSection(sections[0])
+	ranges, err := parseRangesSection(rangesSection)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	ids, err := parseIDsSection(sections[1])
+	ids, err := parseIDsSection(idsSection)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -123,10 +117,12 @@ func parseIngredientDB(data string) ([]Range, IDList, error) {
 	return ranges, ids, nil
 }
 
+// parseRangesSection keeps reversed ranges like 8-3 as they are. They hold
+// no IDs, so merging drops them and they never make an ID fresh.
 func parseRangesSection(section string) ([]Range, error) {
 	var ranges []Range
 
-	for _, line := range strings.Split(section, "\n") {
+	for i, line := range strings.Split(section, "\n") {
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
@@ -134,17 +130,17 @@ func parseRangesSection(section string) ([]Range, error) {
 
 		parts := strings.SplitN(line, "-", 2)
 		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid range: %q", line)
+			return nil, fmt.Errorf("line %d: invalid range: %q", i+1, line)
 		}
 
 		from, err := strconv.Atoi(parts[0])
 		if err != nil {
-			return nil, fmt.Errorf("invalid range start %q: %w", parts[0], err)
+			return nil, fmt.Errorf("line %d: invalid range start %q: %w", i+1, parts[0], err)
 		}
 
 		to, err := strconv.Atoi(parts[1])
 		if err != nil {
-			return nil, fmt.Errorf("invalid range end %q: %w", parts[1], err)
+			return nil, fmt.Errorf("line %d: invalid range end %q: %w", i+1, parts[1], err)
 		}
 
 		ranges = append(ranges, Range{From: from, To: to})
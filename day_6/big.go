@@ -0,0 +1,117 @@
+package day6
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/jibaru/advent-of-code-2025/numeric"
+)
+
+// maxBigBits bounds the size of a power so that a huge exponent fails
+// instead of exhausting memory.
+const maxBigBits = 1 << 24
+
+// AnsBig is the arbitrary-precision version of Ans. It follows the same
+// rules but never overflows.
+func (p Problem) AnsBig() (*big.Int, error) {
+	if !isOperator(p.Operator) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownOperator, p.Operator)
+	}
+	if len(p.Numbers) == 0 {
+		return nil, fmt.Errorf("operator %q needs at least one number", p.Operator)
+	}
+
+	switch p.Operator {
+	case '*':
+		ans := big.NewInt(1)
+		for _, n := range p.Numbers {
+			ans.Mul(ans, big.NewInt(int64(n)))
+		}
+		return ans, nil
+	case '+':
+		ans := new(big.Int)
+		for _, n := range p.Numbers {
+			ans.Add(ans, big.NewInt(int64(n)))
+		}
+		return ans, nil
+	}
+
+	ans := big.NewInt(int64(p.Numbers[0]))
+	for _, n := range p.Numbers[1:] {
+		if err := applyBig(p.Operator, ans, big.NewInt(int64(n))); err != nil {
+			return nil, err
+		}
+	}
+	return ans, nil
+}
+
+// applyBig sets a to a op b.
+func applyBig(op rune, a, b *big.Int) error {
+	switch op {
+	case '-':
+		a.Sub(a, b)
+	case '/':
+		if b.Sign() == 0 {
+			return fmt.Errorf("division by zero")
+		}
+		q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+		if r.Sign() != 0 && (r.Sign() < 0) != (b.Sign() < 0) {
+			q.Sub(q, big.NewInt(1))
+		}
+		a.Set(q)
+	case '%':
+		if b.Sign() == 0 {
+			return fmt.Errorf("modulo by zero")
+		}
+		a.Rem(a, b)
+		if a.Sign() != 0 && (a.Sign() < 0) != (b.Sign() < 0) {
+			a.Add(a, b)
+		}
+	case '^':
+		if b.Sign() < 0 {
+			return fmt.Errorf("negative exponent: %v", b)
+		}
+		if a.CmpAbs(big.NewInt(1)) > 0 && b.Cmp(big.NewInt(int64(maxBigBits/a.BitLen()))) > 0 {
+			return fmt.Errorf("%v^%v has too many digits", a, b)
+		}
+		a.Exp(a, b, nil)
+	case '<':
+		if b.Cmp(a) < 0 {
+			a.Set(b)
+		}
+	case '>':
+		if b.Cmp(a) > 0 {
+			a.Set(b)
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownOperator, op)
+	}
+	return nil
+}
+
+// addAnswer adds the answer of p to total, with math/big when opts ask for
+// it or the answer does not fit in an int. With Strict, an answer or a
+// total that does not fit fails with numeric.ErrOverflow instead.
+func addAnswer(total *numeric.Total, p Problem, opts Options) error {
+	if !opts.Big {
+		ans, err := p.Ans()
+		if err == nil {
+			total.Add(ans)
+			if opts.Strict && total.IsBig() {
+				return fmt.Errorf("adding it to the total: %w", numeric.ErrOverflow)
+			}
+			return nil
+		}
+		if opts.Strict || !errors.Is(err, numeric.ErrOverflow) {
+			return err
+		}
+	}
+
+	ans, err := p.AnsBig()
+	if err != nil {
+		return err
+	}
+	total.AddBig(ans)
+	return nil
+}
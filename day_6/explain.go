@@ -0,0 +1,98 @@
+package day6
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jibaru/advent-of-code-2025/numeric"
+)
+
+// DigitColumn is a column of a cephalopod worksheet read as one number.
+// Column is 0-based like Cut, and Raw holds the column top to bottom,
+// blanks included.
+type DigitColumn struct {
+	Column int
+	Raw    string
+	Number int
+}
+
+// Breakdown is a parsed problem along with the columns it spans. Columns
+// is only set for the cephalopod layout, in reading order.
+type Breakdown struct {
+	Problem
+	Span    Cut
+	Columns []DigitColumn
+}
+
+// String writes the problem as an expression, like "123 * 45 * 6".
+func (p Problem) String() string {
+	if len(p.Numbers) == 0 {
+		return fmt.Sprintf("%c with no numbers", p.Operator)
+	}
+	nums := make([]string, len(p.Numbers))
+	for i, n := range p.Numbers {
+		nums[i] = strconv.Itoa(n)
+	}
+	return strings.Join(nums, " "+string(p.Operator)+" ")
+}
+
+// result formats the answer of the problem the same way sumProblems
+// computes it, or the error that stops it. Answers that only fit in
+// math/big are marked, so the problems that overflow an int stand out.
+func (bd Breakdown) result(useBig bool) string {
+	overflow := ""
+	if !useBig {
+		ans, err := bd.Ans()
+		if err == nil {
+			return strconv.Itoa(ans)
+		}
+		if !errors.Is(err, numeric.ErrOverflow) {
+			return "error: " + err.Error()
+		}
+		overflow = " (overflows int)"
+	}
+
+	ans, err := bd.AnsBig()
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return ans.String() + overflow
+}
+
+// explain writes one line per problem, followed by one line per digit
+// column for the cephalopod layout, like:
+//
+//	problem 1, columns 1-4: 356 * 24 * 1 = 8544
+//	  column 3: "356" -> 356
+func (opts Options) explain(bds []Breakdown) error {
+	if opts.Explain == nil {
+		return nil
+	}
+
+	for i, bd := range bds {
+		if err := opts.explainProblem(i, bd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (opts Options) explainProblem(index int, bd Breakdown) error {
+	if opts.Explain == nil {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(opts.Explain, "problem %d, columns %d-%d: %v = %s\n",
+		index+1, bd.Span.Start+1, bd.Span.End, bd.Problem, bd.result(opts.Big))
+	if err != nil {
+		return err
+	}
+	for _, col := range bd.Columns {
+		if _, err := fmt.Fprintf(opts.Explain, "  column %d: %q -> %d\n", col.Column+1, col.Raw, col.Number); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package day6
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Worksheet is a list of problems that can be written back in either
+// layout.
+type Worksheet []Problem
+
+type Layout string
+
+const (
+	// Horizontal is the part one layout: one number per line for every
+	// problem, with the operators on the last line.
+	Horizontal Layout = "horizontal"
+	// Cephalopod is the part two layout: every column of digits is a
+	// number, read right to left, with the operator under the first column.
+	Cephalopod Layout = "cephalopod"
+)
+
+func ParseWorksheet(data string, layout Layout) (Worksheet, error) {
+	switch layout {
+	case Horizontal, "":
+		return parseWorksheet(data)
+	case Cephalopod:
+		return parseWorksheetCephalopod(data)
+	}
+	return nil, fmt.Errorf("unknown layout: %q", layout)
+}
+
+// ConvertWorksheet parses data in one layout and writes it in the other.
+func ConvertWorksheet(w io.Writer, data string, from, to Layout) error {
+	ws, err := ParseWorksheet(data, from)
+	if err != nil {
+		return err
+	}
+	return ws.Render(w, to)
+}
+
+func (ws Worksheet) Render(w io.Writer, layout Layout) error {
+	switch layout {
+	case Horizontal, "":
+		return ws.RenderHorizontal(w)
+	case Cephalopod:
+		return ws.RenderCephalopod(w)
+	}
+	return fmt.Errorf("unknown layout: %q", layout)
+}
+
+// RenderHorizontal writes one right-aligned number per problem on every
+// line, so all problems need the same count of numbers.
+func (ws Worksheet) RenderHorizontal(w io.Writer) error {
+	digits, err := ws.digits()
+	if err != nil {
+		return err
+	}
+
+	height := len(ws[0].Numbers)
+	widths := make([]int, len(ws))
+	for i, p := range ws {
+		if len(p.Numbers) != height {
+			return &WorksheetError{
+				Problem: i + 1,
+				Err:     fmt.Errorf("has %d numbers, want %d like problem 1", len(p.Numbers), height),
+			}
+		}
+		if height == 0 {
+			return &WorksheetError{Problem: i + 1, Err: fmt.Errorf("has no numbers")}
+		}
+		widths[i] = 1
+		for _, d := range digits[i] {
+			widths[i] = max(widths[i], len(d))
+		}
+	}
+
+	lines := make([][]string, height+1)
+	for i := range ws {
+		for r, d := range digits[i] {
+			lines[r] = append(lines[r], fmt.Sprintf("%*s", widths[i], d))
+		}
+		lines[height] = append(lines[height], fmt.Sprintf("%-*c", widths[i], ws[i].Operator))
+	}
+	return writeLines(w, lines)
+}
+
+// RenderCephalopod writes every number top-down in its own column, the
+// first number of a problem in its rightmost column, with a blank column
+// between problems.
+func (ws Worksheet) RenderCephalopod(w io.Writer) error {
+	digits, err := ws.digits()
+	if err != nil {
+		return err
+	}
+
+	height := 0
+	for _, ds := range digits {
+		for _, d := range ds {
+			height = max(height, len(d))
+		}
+	}
+	if height == 0 {
+		return fmt.Errorf("worksheet has no numbers")
+	}
+
+	lines := make([][]string, height+1)
+	for i, ds := range digits {
+		width := max(len(ds), 1)
+		block := make([][]byte, height)
+		for r := range block {
+			block[r] = []byte(strings.Repeat(" ", width))
+		}
+		for j, d := range ds {
+			for r := range len(d) {
+				block[r][len(ds)-1-j] = d[r]
+			}
+		}
+		for r := range block {
+			lines[r] = append(lines[r], string(block[r]))
+		}
+		lines[height] = append(lines[height], fmt.Sprintf("%-*c", width, ws[i].Operator))
+	}
+	return writeLines(w, lines)
+}
+
+// digits formats the numbers of every problem, checking that the
+// worksheet can be written at all.
+func (ws Worksheet) digits() ([][]string, error) {
+	if len(ws) == 0 {
+		return nil, fmt.Errorf("worksheet has no problems")
+	}
+
+	digits := make([][]string, len(ws))
+	for i, p := range ws {
+		if !isOperator(p.Operator) {
+			return nil, &WorksheetError{Problem: i + 1, Err: fmt.Errorf("%w: %q", ErrUnknownOperator, p.Operator)}
+		}
+		for _, n := range p.Numbers {
+			if n < 0 {
+				return nil, &WorksheetError{Problem: i + 1, Err: fmt.Errorf("cannot write negative number %d", n)}
+			}
+			digits[i] = append(digits[i], strconv.Itoa(n))
+		}
+	}
+	return digits, nil
+}
+
+func writeLines(w io.Writer, lines [][]string) error {
+	for _, cells := range lines {
+		line := strings.TrimRight(strings.Join(cells, " "), " ")
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,19 +1,47 @@
 package day6
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/jibaru/advent-of-code-2025/numeric"
 )
 
+type Options struct {
+	// Big evaluates every problem with math/big instead of int.
+	Big bool
+	// Strict fails with numeric.ErrOverflow, in a WorksheetError naming the
+	// problem, instead of switching to math/big when an int overflows. It
+	// is ignored when Big is set.
+	Strict bool
+	// Explain, when set, receives every problem with the columns it was
+	// read from and its result.
+	Explain io.Writer
+	// Stream reads the input one problem at a time instead of loading it,
+	// for worksheets too wide to fit in memory.
+	Stream bool
+}
+
 func Solve(part int, isTest bool) (any, error) {
+	return SolveWithOptions(part, isTest, Options{})
+}
+
+func SolveWithOptions(part int, isTest bool, opts Options) (any, error) {
 	f := "day_6/input.txt"
 	if isTest {
 		f = "day_6/input-test.txt"
 	}
 
+	if opts.Stream {
+		return solveStream(f, part, opts)
+	}
+
 	body, err := os.ReadFile(f)
 	if err != nil {
 		return nil, err
@@ -21,40 +49,49 @@ func Solve(part int, isTest bool) (any, error) {
 
 	switch part {
 	case 1:
-		return partOne(string(body))
+		return partOne(string(body), opts)
 	case 2:
-		return partTwo(string(body))
+		return partTwo(string(body), opts)
 	}
 
 	return nil, fmt.Errorf("part should be only 1 or 2")
 }
 
-func partOne(data string) (any, error) {
-	ws, err := parseWorksheet(data)
+func partOne(data string, opts Options) (any, error) {
+	bds, err := breakdownWorksheet(data)
 	if err != nil {
 		return nil, err
 	}
-
-	total := 0
-	for _, p := range ws {
-		res := p.Ans()
-		total += res
+	if err := opts.explain(bds); err != nil {
+		return nil, err
 	}
-	return total, nil
+
+	return sumProblems(problemsOf(bds), opts)
 }
 
-func partTwo(data string) (any, error) {
-	ws, err := parseWorksheetCephalopod(data)
+func partTwo(data string, opts Options) (any, error) {
+	bds, err := breakdownCephalopod(data)
 	if err != nil {
 		return nil, err
 	}
+	if err := opts.explain(bds); err != nil {
+		return nil, err
+	}
 
-	total := 0
-	for _, p := range ws {
-		res := p.Ans()
-		total += res
+	return sumProblems(problemsOf(bds), opts)
+}
+
+func sumProblems(ws []Problem, opts Options) (any, error) {
+	var total numeric.Total
+	if opts.Big {
+		total.UseBig()
+	}
+	for i, p := range ws {
+		if err := addAnswer(&total, p, opts); err != nil {
+			return nil, &WorksheetError{Problem: i + 1, Err: err}
+		}
 	}
-	return total, nil
+	return total.Result(), nil
 }
 
 type Problem struct {
@@ -62,65 +99,288 @@ type Problem struct {
 	Operator rune
 }
 
-func (p Problem) Ans() int {
+// Operators lists every operator a worksheet can use. Problems are folded
+// from left to right, so "-" is the first number minus the rest and "^" is
+// ((a^b)^c). "/" rounds towards negative infinity and "%" takes the sign
+// of the divisor, so that a == (a/b)*b + a%b. "<" is the minimum and ">"
+// the maximum. Every operator needs at least one number.
+const Operators = "+*-/%^<>"
+
+var ErrUnknownOperator = errors.New("unknown operator")
+
+func isOperator(r rune) bool {
+	return strings.ContainsRune(Operators, r)
+}
+
+func (p Problem) Ans() (int, error) {
+	if !isOperator(p.Operator) {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownOperator, p.Operator)
+	}
+	if len(p.Numbers) == 0 {
+		return 0, fmt.Errorf("operator %q needs at least one number", p.Operator)
+	}
+
 	switch p.Operator {
 	case '*':
 		ans := 1
 		for _, n := range p.Numbers {
-			ans *= n
+			var err error
+			if ans, err = numeric.Mul(ans, n); err != nil {
+				return 0, err
+			}
 		}
-		return ans
+		return ans, nil
 	case '+':
 		ans := 0
 		for _, n := range p.Numbers {
-			ans += n
+			var err error
+			if ans, err = numeric.Add(ans, n); err != nil {
+				return 0, err
+			}
+		}
+		return ans, nil
+	}
+
+	ans := p.Numbers[0]
+	for _, n := range p.Numbers[1:] {
+		var err error
+		ans, err = apply(p.Operator, ans, n)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return ans, nil
+}
+
+func apply(op rune, a, b int) (int, error) {
+	switch op {
+	case '-':
+		return numeric.Sub(a, b)
+	case '/':
+		if b == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return floorDiv(a, b)
+	case '%':
+		if b == 0 {
+			return 0, fmt.Errorf("modulo by zero")
+		}
+		return floorMod(a, b), nil
+	case '^':
+		if b < 0 {
+			return 0, fmt.Errorf("negative exponent: %d", b)
+		}
+		return powInt(a, b)
+	case '<':
+		return min(a, b), nil
+	case '>':
+		return max(a, b), nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownOperator, op)
+}
+
+// floorDiv divides rounding towards negative infinity, unlike Go's /.
+func floorDiv(a, b int) (int, error) {
+	if a == math.MinInt && b == -1 {
+		return 0, numeric.ErrOverflow
+	}
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q, nil
+}
+
+// floorMod is the remainder matching floorDiv, with the sign of b.
+func floorMod(a, b int) int {
+	r := a % b
+	if r != 0 && (r < 0) != (b < 0) {
+		r += b
+	}
+	return r
+}
+
+func powInt(base, exp int) (int, error) {
+	ans := 1
+	for {
+		var err error
+		if exp&1 == 1 {
+			if ans, err = numeric.Mul(ans, base); err != nil {
+				return 0, err
+			}
+		}
+		exp >>= 1
+		if exp == 0 {
+			return ans, nil
 		}
-		return ans
+		if base, err = numeric.Mul(base, base); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// WorksheetError reports where a worksheet could not be parsed or solved.
+// Problem, Line and Column are 1-based; zero means unknown.
+type WorksheetError struct {
+	Problem int
+	Line    int
+	Column  int
+	Err     error
+}
+
+func (e *WorksheetError) Error() string {
+	var where []string
+	if e.Problem > 0 {
+		where = append(where, fmt.Sprintf("problem %d", e.Problem))
+	}
+	if e.Line > 0 {
+		where = append(where, fmt.Sprintf("line %d", e.Line))
+	}
+	if e.Column > 0 {
+		where = append(where, fmt.Sprintf("column %d", e.Column))
 	}
-	return 0
+	if len(where) == 0 {
+		return e.Err.Error()
+	}
+	return strings.Join(where, ", ") + ": " + e.Err.Error()
+}
+
+func (e *WorksheetError) Unwrap() error {
+	return e.Err
 }
 
+var (
+	ErrMissingOperator = errors.New("missing operator")
+	ErrMissingNumbers  = errors.New("operator has no numbers")
+)
+
 func parseWorksheet(data string) ([]Problem, error) {
-	data = strings.TrimSpace(data)
+	bds, err := breakdownWorksheet(data)
+	if err != nil {
+		return nil, err
+	}
+	return problemsOf(bds), nil
+}
 
-	lines := strings.Split(data, "\n")
-	opLine := strings.TrimSpace(lines[len(lines)-1]) // last line
+// breakdownWorksheet parses the horizontal layout, keeping the columns
+// spanned by every problem.
+func breakdownWorksheet(data string) ([]Breakdown, error) {
+	data = strings.TrimRight(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
 
-	opRe := regexp.MustCompile(`[\+\*]`)
-	operators := opRe.FindAllString(opLine, -1)
+	lines := strings.Split(data, "\n")
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("invalid input")
+	}
+	opLine := lines[len(lines)-1] // last line
+
+	var operators []token
+	for i, tok := range tokenize(opLine) {
+		if len(tok.text) != 1 || !isOperator(rune(tok.text[0])) {
+			return nil, &WorksheetError{
+				Problem: i + 1,
+				Line:    len(lines),
+				Column:  tok.col + 1,
+				Err:     fmt.Errorf("%w: %q", ErrUnknownOperator, tok.text),
+			}
+		}
+		operators = append(operators, tok)
+	}
+	if len(operators) == 0 {
+		return nil, &WorksheetError{Line: len(lines), Err: ErrMissingOperator}
+	}
 
-	matrix := parseNumbersStr(lines[:len(lines)-1])
+	matrix, err := parseNumbersStr(lines[:len(lines)-1], len(operators))
+	if err != nil {
+		return nil, err
+	}
 	matrix = append(matrix, operators)
 	matrix = transposeMatrix(matrix)
 
-	var problems []Problem
+	var bds []Breakdown
 	for _, row := range matrix {
-		p := Problem{}
-		for _, s := range row {
-			if s == "*" || s == "+" {
-				p.Operator = rune(s[0])
-			} else {
-				n, err := strconv.Atoi(s)
-				if err != nil {
-					return nil, err
+		op := row[len(row)-1]
+		bd := Breakdown{
+			Problem: Problem{Operator: rune(op.text[0])},
+			Span:    Cut{Start: op.col, End: op.col + 1},
+		}
+		for _, tok := range row[:len(row)-1] {
+			n, _ := strconv.Atoi(tok.text) // validated by parseNumbersStr
+			bd.Numbers = append(bd.Numbers, n)
+			bd.Span.Start = min(bd.Span.Start, tok.col)
+			bd.Span.End = max(bd.Span.End, tok.col+len(tok.text))
+		}
+		bds = append(bds, bd)
+	}
+
+	return bds, nil
+}
+
+func problemsOf(bds []Breakdown) []Problem {
+	problems := make([]Problem, len(bds))
+	for i, bd := range bds {
+		problems[i] = bd.Problem
+	}
+	return problems
+}
+
+type token struct {
+	text string
+	col  int
+}
+
+var tokenRe = regexp.MustCompile(`\S+`)
+
+func tokenize(line string) []token {
+	var tokens []token
+	for _, loc := range tokenRe.FindAllStringIndex(line, -1) {
+		tokens = append(tokens, token{text: line[loc[0]:loc[1]], col: loc[0]})
+	}
+	return tokens
+}
+
+// parseNumbersStr splits every line into its numbers, checking that each
+// line has exactly one number per problem.
+func parseNumbersStr(lines []string, problems int) ([][]token, error) {
+	allNums := [][]token{}
+	for i, line := range lines {
+		tokens := tokenize(line)
+		if len(tokens) != problems {
+			col := 0
+			if len(tokens) > problems {
+				col = tokens[problems].col + 1
+			}
+			return nil, &WorksheetError{
+				Line:   i + 1,
+				Column: col,
+				Err:    fmt.Errorf("found %d numbers, want one per operator (%d)", len(tokens), problems),
+			}
+		}
+
+		for j, tok := range tokens {
+			if !isDigits(tok.text) {
+				return nil, &WorksheetError{
+					Problem: j + 1,
+					Line:    i + 1,
+					Column:  tok.col + 1,
+					Err:     fmt.Errorf("invalid number %q", tok.text),
 				}
-				p.Numbers = append(p.Numbers, n)
+			}
+			if _, err := strconv.Atoi(tok.text); err != nil {
+				return nil, &WorksheetError{Problem: j + 1, Line: i + 1, Column: tok.col + 1, Err: err}
 			}
 		}
-		problems = append(problems, p)
+		allNums = append(allNums, tokens)
 	}
-
-	return problems, nil
+	return allNums, nil
 }
 
-func parseNumbersStr(lines []string) [][]string {
-	numRe := regexp.MustCompile(`\d+`)
-	allNums := [][]string{}
-	for _, line := range lines {
-		nums := numRe.FindAllString(line, -1)
-		allNums = append(allNums, nums)
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
 	}
-	return allNums
+	return s != ""
 }
 
 func transposeMatrix[T any](matrix [][]T) [][]T {
@@ -141,6 +401,17 @@ func transposeMatrix[T any](matrix [][]T) [][]T {
 }
 
 func parseWorksheetCephalopod(input string) ([]Problem, error) {
+	bds, err := breakdownCephalopod(input)
+	if err != nil {
+		return nil, err
+	}
+	return problemsOf(bds), nil
+}
+
+// breakdownCephalopod parses the cephalopod layout, keeping the cut and
+// the digit columns of every problem.
+func breakdownCephalopod(input string) ([]Breakdown, error) {
+	input = strings.ReplaceAll(input, "\r\n", "\n")
 	lines := strings.Split(strings.TrimRight(input, "\n"), "\n")
 	if len(lines) < 2 {
 		return nil, fmt.Errorf("invalid input")
@@ -149,49 +420,69 @@ func parseWorksheetCephalopod(input string) ([]Problem, error) {
 	opLine := lines[len(lines)-1]
 	dataLines := lines[:len(lines)-1]
 
-	cuts := computeSlicesFromOperators(opLine)
+	for i, r := range opLine {
+		if r != ' ' && !isOperator(r) {
+			return nil, &WorksheetError{
+				Line:   len(lines),
+				Column: i + 1,
+				Err:    fmt.Errorf("%w: %q", ErrUnknownOperator, r),
+			}
+		}
+	}
+
+	width := 0
+	for _, ln := range lines {
+		width = max(width, len(ln))
+	}
+
+	cuts := computeSlicesFromOperators(opLine, width)
+	if len(cuts) == 0 {
+		return nil, &WorksheetError{Line: len(lines), Err: ErrMissingOperator}
+	}
 
 	var matrix [][]string
-	for _, ln := range dataLines {
+	for i, ln := range dataLines {
+		// anything left of the first operator belongs to no problem
+		if j := strings.IndexFunc(ln[:min(len(ln), cuts[0].Start)], isNotSpace); j >= 0 {
+			return nil, &WorksheetError{Line: i + 1, Column: j + 1, Err: ErrMissingOperator}
+		}
 		row := sliceByCuts(ln, cuts)
 		matrix = append(matrix, row)
 	}
 
 	inv := transposeMatrix(matrix)
 
-	// extract operators
-	opSlices := sliceByCuts(opLine, cuts)
-	var ops []rune
-	for _, s := range opSlices {
-		s = strings.TrimSpace(s)
-		if len(s) == 1 {
-			ops = append(ops, rune(s[0]))
-		} else {
-			ops = append(ops, '+') // default
-		}
-	}
-
 	// to problem struct
-	var out []Problem
+	var out []Breakdown
 	for i, row := range inv {
-		out = append(out, cephalopodToProblem(row, ops[i]))
+		bd, err := cephalopodToProblem(i, row, cuts[i], rune(opLine[cuts[i].Start]))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, bd)
 	}
 
 	return out, nil
 }
 
+func isNotSpace(r rune) bool {
+	return r != ' '
+}
+
 type Cut struct {
 	Start int
 	End   int
 }
 
-func computeSlicesFromOperators(line string) []Cut {
+// computeSlicesFromOperators cuts the worksheet at every operator. The last
+// cut runs to width so that digits past the end of the operator line are
+// kept.
+func computeSlicesFromOperators(line string, width int) []Cut {
 	var cuts []Cut
 	var idxs []int
 
 	for i, r := range line {
-		switch r {
-		case '+', '*':
+		if isOperator(r) {
 			idxs = append(idxs, i)
 		}
 	}
@@ -203,7 +494,7 @@ func computeSlicesFromOperators(line string) []Cut {
 		if i+1 < len(idxs) {
 			end = idxs[i+1]
 		} else {
-			end = len(line)
+			end = max(width, len(line))
 		}
 		cuts = append(cuts, Cut{Start: start, End: end})
 	}
@@ -227,7 +518,11 @@ func sliceByCuts(line string, cuts []Cut) []string {
 	return res
 }
 
-func cephalopodToProblem(parts []string, op rune) Problem {
+// cephalopodToProblem reads the numbers of the problem at index from its
+// cut, one number per digit column, right to left. A blank column between
+// two digit columns means two problems are sharing one operator, and an
+// operator without any digit column under it has lost its numbers.
+func cephalopodToProblem(index int, parts []string, cut Cut, op rune) (Breakdown, error) {
 	// parts are the block substrings for each numeric row (operator excluded).
 	// Find max width and pad-right so we can index columns.
 	maxW := 0
@@ -246,24 +541,56 @@ func cephalopodToProblem(parts []string, op rune) Problem {
 
 	// build numbers
 	nums := []int{}
+	var columns []DigitColumn
+	gap := -1
 	for c := maxW - 1; c >= 0; c-- {
-		var b strings.Builder
+		var b, raw strings.Builder
 		for r := 0; r < len(padded); r++ { // top to bottom
 			ch := padded[r][c]
-			if ch != ' ' {
-				b.WriteByte(ch)
+			raw.WriteByte(ch)
+			if ch == ' ' {
+				continue
 			}
+			if ch < '0' || ch > '9' {
+				return Breakdown{}, &WorksheetError{
+					Problem: index + 1,
+					Line:    r + 1,
+					Column:  cut.Start + c + 1,
+					Err:     fmt.Errorf("invalid digit %q", ch),
+				}
+			}
+			b.WriteByte(ch)
 		}
 		if b.Len() == 0 {
 			// empty column must skip
+			if len(nums) > 0 && gap < 0 {
+				gap = c
+			}
 			continue
 		}
+		if gap >= 0 {
+			return Breakdown{}, &WorksheetError{Problem: index + 1, Column: cut.Start + gap + 1, Err: ErrMissingOperator}
+		}
 		n, err := strconv.Atoi(b.String())
 		if err != nil {
-			n = 0 // TODO: return error
+			return Breakdown{}, &WorksheetError{Problem: index + 1, Column: cut.Start + c + 1, Err: err}
 		}
 		nums = append(nums, n)
+		columns = append(columns, DigitColumn{Column: cut.Start + c, Raw: raw.String(), Number: n})
+	}
+
+	if len(nums) == 0 {
+		return Breakdown{}, &WorksheetError{
+			Problem: index + 1,
+			Line:    len(parts) + 1,
+			Column:  cut.Start + 1,
+			Err:     ErrMissingNumbers,
+		}
 	}
 
-	return Problem{Numbers: nums, Operator: op}
+	return Breakdown{
+		Problem: Problem{Numbers: nums, Operator: op},
+		Span:    cut,
+		Columns: columns,
+	}, nil
 }
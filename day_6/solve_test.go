@@ -0,0 +1,144 @@
+package day6
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jibaru/advent-of-code-2025/numeric"
+)
+
+const overflowingWorksheet = "9999999999 3\n9999999999 4\n*          +\n"
+
+func TestOverflowFallsBackToBig(t *testing.T) {
+	got, err := partOne(overflowingWorksheet, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := new(big.Int).SetString("99999999980000000008", 10)
+	if n, ok := got.(*big.Int); !ok || n.Cmp(want) != 0 {
+		t.Fatalf("partOne() = %v, want %v", got, want)
+	}
+}
+
+func TestStrictReportsOverflowingProblem(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		problem int
+	}{
+		{name: "answer", data: overflowingWorksheet, problem: 1},
+		{name: "total", data: "9223372036854775807 1\n+                   +\n", problem: 2},
+	}
+
+	for _, tt := range tests {
+		_, err := partOne(tt.data, Options{Strict: true})
+		var we *WorksheetError
+		if !errors.As(err, &we) || we.Problem != tt.problem || !errors.Is(err, numeric.ErrOverflow) {
+			t.Errorf("%s: error = %v, want an overflow in problem %d", tt.name, err, tt.problem)
+		}
+	}
+}
+
+func TestExplainMarksOverflow(t *testing.T) {
+	var out strings.Builder
+	if _, err := partOne(overflowingWorksheet, Options{Explain: &out}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(out.String(), "\n")
+	if !strings.HasSuffix(lines[0], "(overflows int)") || strings.Contains(lines[1], "overflows") {
+		t.Fatalf("explain output does not mark only problem 1:\n%s", out.String())
+	}
+}
+
+func TestEveryOperatorNeedsNumbers(t *testing.T) {
+	for _, op := range Operators {
+		p := Problem{Operator: op}
+		if ans, err := p.Ans(); err == nil {
+			t.Errorf("%c: Ans() = %d, want an error", op, ans)
+		}
+		if ans, err := p.AnsBig(); err == nil {
+			t.Errorf("%c: AnsBig() = %v, want an error", op, ans)
+		}
+	}
+}
+
+func addWorksheetSeeds(f *testing.F) {
+	data, err := os.ReadFile("input-test.txt")
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(string(data))
+	f.Add(strings.ReplaceAll(string(data), "\n", "\r\n"))
+	f.Add("1 2\n+ x\n")
+	f.Add("12  3\n 4 56\n*  + \n")
+	f.Add("12   \n 3   \n+   *\n")
+	f.Add("")
+}
+
+// checkParsed fails on results a parser must never return: either an error
+// or problems with numbers that can be solved without panicking.
+func checkParsed(t *testing.T, ws []Problem, err error) {
+	if err != nil {
+		if ws != nil {
+			t.Fatalf("got problems %v along with error %v", ws, err)
+		}
+		return
+	}
+	for _, p := range ws {
+		if !isOperator(p.Operator) {
+			t.Fatalf("parsed unknown operator %q", p.Operator)
+		}
+		if len(p.Numbers) == 0 {
+			t.Fatalf("parsed operator %q without numbers", p.Operator)
+		}
+	}
+	sumProblems(ws, Options{})
+}
+
+func FuzzParseWorksheet(f *testing.F) {
+	addWorksheetSeeds(f)
+	f.Fuzz(func(t *testing.T, data string) {
+		ws, err := parseWorksheet(data)
+		checkParsed(t, ws, err)
+	})
+}
+
+func FuzzParseWorksheetCephalopod(f *testing.F) {
+	addWorksheetSeeds(f)
+	f.Fuzz(func(t *testing.T, data string) {
+		ws, err := parseWorksheetCephalopod(data)
+		checkParsed(t, ws, err)
+	})
+}
+
+// FuzzStreamWorksheet checks that streaming a worksheet gives the same
+// problems as parsing it at once, and fails exactly when parsing does.
+func FuzzStreamWorksheet(f *testing.F) {
+	addWorksheetSeeds(f)
+	f.Fuzz(func(t *testing.T, data string) {
+		for _, layout := range []Layout{Horizontal, Cephalopod} {
+			want, wantErr := ParseWorksheet(data, layout)
+
+			var got Worksheet
+			var gotErr error
+			for p, err := range StreamWorksheet(strings.NewReader(data), int64(len(data)), layout) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				got = append(got, p)
+			}
+
+			if (wantErr == nil) != (gotErr == nil) {
+				t.Fatalf("%s: ParseWorksheet error = %v, StreamWorksheet error = %v", layout, wantErr, gotErr)
+			}
+			if wantErr == nil && !reflect.DeepEqual(got, want) {
+				t.Fatalf("%s: StreamWorksheet = %v, ParseWorksheet = %v", layout, got, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,363 @@
+package day6
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jibaru/advent-of-code-2025/numeric"
+)
+
+// StreamWorksheet parses the worksheet in r one problem at a time, reading
+// every line side by side. Only the line offsets and the problem being
+// read are kept in memory, so its size depends on the height of the
+// worksheet and not on its width. Parsing stops after the first error.
+func StreamWorksheet(r io.ReaderAt, size int64, layout Layout) iter.Seq2[Problem, error] {
+	return func(yield func(Problem, error) bool) {
+		for bd, err := range streamBreakdowns(r, size, layout) {
+			if !yield(bd.Problem, err) {
+				return
+			}
+		}
+	}
+}
+
+func streamBreakdowns(r io.ReaderAt, size int64, layout Layout) iter.Seq2[Breakdown, error] {
+	return func(yield func(Breakdown, error) bool) {
+		lines, err := scanLines(r, size)
+		if err == nil && len(lines) < 2 {
+			err = fmt.Errorf("invalid input")
+		}
+		if err != nil {
+			yield(Breakdown{}, err)
+			return
+		}
+
+		switch layout {
+		case Horizontal, "":
+			err = streamHorizontal(r, lines, yield)
+		case Cephalopod:
+			err = streamCephalopod(r, lines, yield)
+		default:
+			err = fmt.Errorf("unknown layout: %q", layout)
+		}
+		if err != nil {
+			yield(Breakdown{}, err)
+		}
+	}
+}
+
+// errStopped tells the stream functions that yield asked to stop.
+var errStopped = errors.New("stopped")
+
+func solveStream(path string, part int, opts Options) (any, error) {
+	layout := Horizontal
+	switch part {
+	case 1:
+	case 2:
+		layout = Cephalopod
+	default:
+		return nil, fmt.Errorf("part should be only 1 or 2")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	var total numeric.Total
+	if opts.Big {
+		total.UseBig()
+	}
+	i := 0
+	for bd, err := range streamBreakdowns(f, info.Size(), layout) {
+		if err != nil {
+			return nil, err
+		}
+		if err := opts.explainProblem(i, bd); err != nil {
+			return nil, err
+		}
+		if err := addAnswer(&total, bd.Problem, opts); err != nil {
+			return nil, &WorksheetError{Problem: i + 1, Err: err}
+		}
+		i++
+	}
+	return total.Result(), nil
+}
+
+type lineSpan struct {
+	offset int64
+	length int
+	tokens int
+}
+
+// scanLines finds where every line starts and how long it is, without
+// the "\r" of a "\r\n" and dropping the trailing empty lines like the
+// other parsers do.
+func scanLines(r io.ReaderAt, size int64) ([]lineSpan, error) {
+	br := bufio.NewReader(io.NewSectionReader(r, 0, size))
+
+	var lines []lineSpan
+	var cur lineSpan
+	var pos int64
+	var prev byte
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		pos++
+
+		if b == '\n' {
+			if prev == '\r' {
+				cur.length--
+			}
+			lines = append(lines, cur)
+			cur = lineSpan{offset: pos}
+			prev = b
+			continue
+		}
+		if !isSpaceByte(b) && (cur.length == 0 || isSpaceByte(prev)) {
+			cur.tokens++
+		}
+		cur.length++
+		prev = b
+	}
+	lines = append(lines, cur)
+
+	for len(lines) > 0 && lines[len(lines)-1].length == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines, nil
+}
+
+func (l lineSpan) reader(r io.ReaderAt) *bufio.Reader {
+	return bufio.NewReader(io.NewSectionReader(r, l.offset, int64(l.length)))
+}
+
+// isSpaceByte matches the bytes that tokenRe treats as blanks.
+func isSpaceByte(b byte) bool {
+	switch b {
+	case ' ', '\t', '\n', '\f', '\r':
+		return true
+	}
+	return false
+}
+
+// tokenReader reads the same tokens as tokenize, one at a time.
+type tokenReader struct {
+	r   *bufio.Reader
+	col int
+}
+
+// next returns io.EOF once the line has no more tokens.
+func (t *tokenReader) next() (token, error) {
+	var b strings.Builder
+	start := t.col
+	for {
+		c, err := t.r.ReadByte()
+		if err == io.EOF && b.Len() > 0 {
+			return token{text: b.String(), col: start}, nil
+		}
+		if err != nil {
+			return token{}, err
+		}
+		t.col++
+
+		if isSpaceByte(c) {
+			if b.Len() > 0 {
+				return token{text: b.String(), col: start}, nil
+			}
+			start = t.col
+			continue
+		}
+		b.WriteByte(c)
+	}
+}
+
+// streamHorizontal reads one token from every line for each problem.
+func streamHorizontal(r io.ReaderAt, lines []lineSpan, yield func(Breakdown, error) bool) error {
+	opLine := lines[len(lines)-1]
+	problems := opLine.tokens
+	if problems == 0 {
+		return &WorksheetError{Line: len(lines), Err: ErrMissingOperator}
+	}
+
+	readers := make([]*tokenReader, len(lines))
+	for i, l := range lines {
+		readers[i] = &tokenReader{r: l.reader(r)}
+	}
+
+	for i, l := range lines[:len(lines)-1] {
+		if l.tokens == problems {
+			continue
+		}
+		col := 0
+		if l.tokens > problems {
+			extra := &tokenReader{r: l.reader(r)}
+			for range problems + 1 {
+				tok, err := extra.next()
+				if err != nil {
+					return err
+				}
+				col = tok.col + 1
+			}
+		}
+		return &WorksheetError{
+			Line:   i + 1,
+			Column: col,
+			Err:    fmt.Errorf("found %d numbers, want one per operator (%d)", l.tokens, problems),
+		}
+	}
+
+	for j := range problems {
+		op, err := readers[len(lines)-1].next()
+		if err != nil {
+			return err
+		}
+		if len(op.text) != 1 || !isOperator(rune(op.text[0])) {
+			return &WorksheetError{
+				Problem: j + 1,
+				Line:    len(lines),
+				Column:  op.col + 1,
+				Err:     fmt.Errorf("%w: %q", ErrUnknownOperator, op.text),
+			}
+		}
+
+		bd := Breakdown{
+			Problem: Problem{Operator: rune(op.text[0])},
+			Span:    Cut{Start: op.col, End: op.col + 1},
+		}
+		for i, tr := range readers[:len(lines)-1] {
+			tok, err := tr.next()
+			if err != nil {
+				return err
+			}
+			if !isDigits(tok.text) {
+				return &WorksheetError{
+					Problem: j + 1,
+					Line:    i + 1,
+					Column:  tok.col + 1,
+					Err:     fmt.Errorf("invalid number %q", tok.text),
+				}
+			}
+			n, err := strconv.Atoi(tok.text)
+			if err != nil {
+				return &WorksheetError{Problem: j + 1, Line: i + 1, Column: tok.col + 1, Err: err}
+			}
+			bd.Numbers = append(bd.Numbers, n)
+			bd.Span.Start = min(bd.Span.Start, tok.col)
+			bd.Span.End = max(bd.Span.End, tok.col+len(tok.text))
+		}
+
+		if !yield(bd, nil) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// streamCephalopod walks the columns left to right, collecting the digits
+// under the current operator until the next one starts a new problem.
+func streamCephalopod(r io.ReaderAt, lines []lineSpan, yield func(Breakdown, error) bool) error {
+	width := 0
+	readers := make([]*bufio.Reader, len(lines))
+	for i, l := range lines {
+		width = max(width, l.length)
+		readers[i] = l.reader(r)
+	}
+	opLine := len(lines) - 1
+
+	var (
+		index int
+		cut   Cut
+		op    rune
+		parts = make([][]byte, opLine)
+	)
+	flush := func(end int) error {
+		cut.End = end
+		strs := make([]string, len(parts))
+		for i, p := range parts {
+			strs[i] = string(p)
+			parts[i] = parts[i][:0]
+		}
+		bd, err := cephalopodToProblem(index, strs, cut, op)
+		if err != nil {
+			return err
+		}
+		index++
+		if !yield(bd, nil) {
+			return errStopped
+		}
+		return nil
+	}
+
+	for c := range width {
+		if c < lines[opLine].length {
+			b, err := readers[opLine].ReadByte()
+			if err != nil {
+				return err
+			}
+			if b != ' ' && !isOperator(rune(b)) {
+				return &WorksheetError{
+					Line:   len(lines),
+					Column: c + 1,
+					Err:    fmt.Errorf("%w: %q", ErrUnknownOperator, b),
+				}
+			}
+			if isOperator(rune(b)) {
+				if op != 0 {
+					if err := flush(c); err != nil {
+						return stopped(err)
+					}
+				}
+				cut.Start, op = c, rune(b)
+			}
+		}
+
+		for i := range parts {
+			if c >= lines[i].length {
+				continue
+			}
+			b, err := readers[i].ReadByte()
+			if err != nil {
+				return err
+			}
+			if op == 0 {
+				// anything left of the first operator belongs to no problem
+				if b != ' ' {
+					return &WorksheetError{Line: i + 1, Column: c + 1, Err: ErrMissingOperator}
+				}
+				continue
+			}
+			parts[i] = append(parts[i], b)
+		}
+	}
+
+	if op == 0 {
+		return &WorksheetError{Line: len(lines), Err: ErrMissingOperator}
+	}
+	return stopped(flush(width))
+}
+
+// stopped turns errStopped back into a clean end of the stream.
+func stopped(err error) error {
+	if errors.Is(err, errStopped) {
+		return nil
+	}
+	return err
+}
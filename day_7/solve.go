@@ -1,12 +1,33 @@
 package day7
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 	"os"
-	"strings"
+
+	"github.com/jibaru/advent-of-code-2025/containers"
+	"github.com/jibaru/advent-of-code-2025/grid"
+	"github.com/jibaru/advent-of-code-2025/numeric"
 )
 
+// ErrOverflow is returned by the int path when the timelines do not fit in
+// an int. It is numeric.ErrOverflow, so either can be matched with errors.Is.
+var ErrOverflow = numeric.ErrOverflow
+
+type Options struct {
+	// Big counts the part two timelines with math/big instead of int.
+	Big bool
+	// Modulus, when positive, counts the part two timelines modulo it
+	// instead of exactly.
+	Modulus int
+}
+
 func Solve(part int, isTest bool) (any, error) {
+	return SolveWithOptions(part, isTest, Options{})
+}
+
+func SolveWithOptions(part int, isTest bool, opts Options) (any, error) {
 	f := "day_7/input.txt"
 	if isTest {
 		f = "day_7/input-test.txt"
@@ -19,29 +40,32 @@ func Solve(part int, isTest bool) (any, error) {
 
 	switch part {
 	case 1:
-		return partOne(string(body))
+		return partOne(string(body), opts)
 	case 2:
-		return partTwo(string(body))
+		return partTwo(string(body), opts)
 	}
 
 	return nil, fmt.Errorf("part should be only 1 or 2")
 }
 
-func partOne(data string) (any, error) {
-	grid := parseGrid(data)
-	start := grid.StartPosition()
-	beams := NewUniqueQueue()
+func partOne(data string, _ Options) (any, error) {
+	g, err := parseGrid(data)
+	if err != nil {
+		return nil, err
+	}
+	start := g.StartPosition()
+	beams := containers.NewUniqueQueue[Pos]()
 	beams.Put(start)
 	splits := 0
 
 	for !beams.IsEmpty() {
 		pos, _ := beams.Pop()
 		pos = pos.Down()
-		if !grid.InBounds(pos) {
+		if !g.InBounds(pos) {
 			continue
 		}
 
-		if grid.InSplitter(pos) {
+		if g.InSplitter(pos) {
 			left, right := pos.Left(), pos.Right()
 			beams.Put(left)
 			beams.Put(right)
@@ -55,178 +79,175 @@ func partOne(data string) (any, error) {
 	return splits, nil
 }
 
-func partTwo(data string) (any, error) {
-	grid := parseGrid(data)
-	start := grid.StartPosition()
+func partTwo(data string, opts Options) (any, error) {
+	g, err := parseGrid(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case opts.Modulus < 0:
+		return nil, fmt.Errorf("modulus must be positive, got %d", opts.Modulus)
+	case opts.Modulus > 0:
+		m := opts.Modulus
+		return countTimelines(g, 0, 1%m, func(a, b int) (int, error) {
+			return addMod(a, b, m), nil
+		})
+	case !opts.Big:
+		timelines, err := countTimelines(g, 0, 1, numeric.Add)
+		if !errors.Is(err, ErrOverflow) {
+			return timelines, err
+		}
+	}
+
+	return countTimelines(g, new(big.Int), big.NewInt(1), addBig)
+}
+
+// countTimelines follows every timeline from the start down to the bottom,
+// merging the ones that reach the same cell. The multiplicities are added
+// with add, so the same walk counts in int, math/big or modulo a number.
+func countTimelines[T any](g Grid, zero, one T, add func(a, b T) (T, error)) (T, error) {
+	start := g.StartPosition()
 
 	// propagation tail: positions + multiplicity
-	q := NewTimelineQueue()
-	q.Put(start, 1)
+	q := NewTimelineQueue(add)
+	if err := q.Put(start, one); err != nil {
+		return zero, err
+	}
 
 	// how many timelines reach each cell before propagating
-	counts := make(map[Pos]int)
+	counts := make(map[Pos]T)
 
-	timelines := int(0)
+	timelines := zero
 
 	for !q.IsEmpty() {
 		pos, val := q.Pop()
 
 		// If this value had been accumulated before, add it.
-		val += counts[pos]
-		delete(counts, pos)
+		if c, ok := counts[pos]; ok {
+			var err error
+			if val, err = add(val, c); err != nil {
+				return zero, err
+			}
+			delete(counts, pos)
+		}
 
 		down := pos.Down()
-		if !grid.InBounds(down) {
-			timelines += val
+		if !g.InBounds(down) {
+			var err error
+			if timelines, err = add(timelines, val); err != nil {
+				return zero, err
+			}
 			continue
 		}
 
-		if grid.InSplitter(down) {
-			left := down.Left()
-			right := down.Right()
+		next := []Pos{down}
+		if g.InSplitter(down) {
+			next = []Pos{down.Left(), down.Right()}
+		}
 
-			// Only queue if it's the first time that cell has been touched
-			// If there's already an accumulated value, add it later
-			if q.Has(left) {
-				counts[left] += val
+		// Only queue if it's the first time that cell has been touched
+		// If there's already an accumulated value, add it later
+		for _, p := range next {
+			var err error
+			if q.Has(p) {
+				err = accumulate(counts, p, val, add)
 			} else {
-				q.Put(left, val)
+				err = q.Put(p, val)
 			}
-			if q.Has(right) {
-				counts[right] += val
-			} else {
-				q.Put(right, val)
+			if err != nil {
+				return zero, err
 			}
-			continue
-		}
-
-		// movimiento normal hacia abajo
-		if q.Has(down) {
-			counts[down] += val
-		} else {
-			q.Put(down, val)
 		}
 	}
 
 	return timelines, nil
 }
 
-type Grid [][]rune
-
-type Pos struct {
-	Row int
-	Col int
+// addMod adds a and b, both already reduced modulo m, without overflowing.
+func addMod(a, b, m int) int {
+	if a >= m-b {
+		return a - (m - b)
+	}
+	return a + b
 }
 
-func parseGrid(data string) Grid {
-	var g Grid
-	for _, line := range strings.Split(data, "\n") {
-		g = append(g, []rune(line))
-	}
-	return g
+func addBig(a, b *big.Int) (*big.Int, error) {
+	return new(big.Int).Add(a, b), nil
 }
 
-func (g Grid) StartPosition() Pos {
-	var p Pos
-	for row, line := range g {
-		for col, char := range line {
-			if char == 'S' {
-				p.Row = row
-				p.Col = col
-				return p
-			}
+// accumulate adds v to m[p], treating a missing entry as zero.
+func accumulate[T any](m map[Pos]T, p Pos, v T, add func(a, b T) (T, error)) error {
+	if old, ok := m[p]; ok {
+		var err error
+		if v, err = add(old, v); err != nil {
+			return err
 		}
 	}
-	return p
-}
-
-func (g Grid) InBounds(p Pos) bool {
-	return p.Row >= 0 && p.Row < len(g) && p.Col >= 0
-}
-
-func (g Grid) InSplitter(p Pos) bool {
-	return g.InBounds(p) && g[p.Row][p.Col] == '^'
+	m[p] = v
+	return nil
 }
 
-func (p Pos) Down() Pos {
-	return Pos{p.Row + 1, p.Col}
+type Grid struct {
+	grid.Grid[rune]
 }
 
-func (p Pos) Left() Pos {
-	return Pos{p.Row, p.Col - 1}
-}
-
-func (p Pos) Right() Pos {
-	return Pos{p.Row, p.Col + 1}
-}
+type Pos = grid.Pos
 
-type UniqueQueue struct {
-	elements map[Pos]bool
-	queue    []Pos
-}
-
-func NewUniqueQueue() *UniqueQueue {
-	return &UniqueQueue{
-		elements: make(map[Pos]bool),
-		queue:    make([]Pos, 0),
-	}
-}
-
-func (uq *UniqueQueue) Put(pos Pos) {
-	if !uq.elements[pos] {
-		uq.elements[pos] = true
-		uq.queue = append(uq.queue, pos)
+func parseGrid(data string) (Grid, error) {
+	g, err := grid.ParseRunes(data)
+	if err != nil {
+		return Grid{}, err
 	}
+	return Grid{g}, nil
 }
 
-func (uq *UniqueQueue) Pop() (Pos, bool) {
-	if len(uq.queue) == 0 {
-		return Pos{}, false
-	}
-	pos := uq.queue[0]
-	uq.queue = uq.queue[1:]
-	return pos, true
+func (g Grid) StartPosition() Pos {
+	p, _ := g.Find('S')
+	return p
 }
 
-func (uq *UniqueQueue) IsEmpty() bool {
-	return len(uq.queue) == 0
+func (g Grid) InSplitter(p Pos) bool {
+	char, ok := g.At(p)
+	return ok && char == '^'
 }
 
 // TimelineQueue maintains a queue of positions with associated multiplicity.
 // If a position is already in the queue, it is not duplicated: it is added to the counts.
-type TimelineQueue struct {
-	queue    []Pos
+type TimelineQueue[T any] struct {
+	queue    *containers.Queue[Pos]
 	elements map[Pos]bool
-	vals     map[Pos]int
+	vals     map[Pos]T
+	add      func(a, b T) (T, error)
 }
 
-func NewTimelineQueue() *TimelineQueue {
-	return &TimelineQueue{
-		queue:    make([]Pos, 0),
+func NewTimelineQueue[T any](add func(a, b T) (T, error)) *TimelineQueue[T] {
+	return &TimelineQueue[T]{
+		queue:    containers.NewQueue[Pos](0),
 		elements: make(map[Pos]bool),
-		vals:     make(map[Pos]int),
+		vals:     make(map[Pos]T),
+		add:      add,
 	}
 }
 
-func (t *TimelineQueue) Put(p Pos, v int) {
+func (t *TimelineQueue[T]) Put(p Pos, v T) error {
 	if !t.elements[p] {
-		t.queue = append(t.queue, p)
+		t.queue.Put(p)
 		t.elements[p] = true
 	}
-	t.vals[p] += v
+	return accumulate(t.vals, p, v, t.add)
 }
 
-func (t *TimelineQueue) Has(p Pos) bool {
+func (t *TimelineQueue[T]) Has(p Pos) bool {
 	return t.elements[p]
 }
 
-func (t *TimelineQueue) Pop() (Pos, int) {
-	if len(t.queue) == 0 {
-		return Pos{}, 0
+func (t *TimelineQueue[T]) Pop() (Pos, T) {
+	p, ok := t.queue.Pop()
+	if !ok {
+		var zero T
+		return Pos{}, zero
 	}
-
-	p := t.queue[0]
-	t.queue = t.queue[1:]
 	delete(t.elements, p)
 
 	v := t.vals[p]
@@ -235,6 +256,6 @@ func (t *TimelineQueue) Pop() (Pos, int) {
 	return p, v
 }
 
-func (t *TimelineQueue) IsEmpty() bool {
-	return len(t.queue) == 0
+func (t *TimelineQueue[T]) IsEmpty() bool {
+	return t.queue.IsEmpty()
 }
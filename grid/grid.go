@@ -0,0 +1,240 @@
+package grid
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// Grid is a rectangular grid of cells stored row by row in a single slice.
+// Copies of a Grid share their cells; use Clone for an independent one.
+type Grid[T comparable] struct {
+	rows  int
+	cols  int
+	cells []T
+}
+
+func New[T comparable](rows, cols int, fill T) Grid[T] {
+	cells := make([]T, rows*cols)
+	for i := range cells {
+		cells[i] = fill
+	}
+	return Grid[T]{rows: rows, cols: cols, cells: cells}
+}
+
+// FromRows builds a grid from rows that must all have the same length.
+func FromRows[T comparable](rows [][]T) (Grid[T], error) {
+	if len(rows) == 0 {
+		return Grid[T]{}, nil
+	}
+
+	g := Grid[T]{rows: len(rows), cols: len(rows[0])}
+	g.cells = make([]T, 0, g.rows*g.cols)
+	for r, row := range rows {
+		if len(row) != g.cols {
+			return Grid[T]{}, fmt.Errorf("row %d has %d cells, expected %d", r+1, len(row), g.cols)
+		}
+		g.cells = append(g.cells, row...)
+	}
+	return g, nil
+}
+
+// Parse builds a grid from one line per row, converting every rune with
+// cell. Empty lines are skipped, but lines of spaces are rows like any
+// other, and all of them must have the same number of runes.
+func Parse[T comparable](data string, cell func(r rune) (T, error)) (Grid[T], error) {
+	var rows [][]T
+	for lineNum, line := range strings.Split(data, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" {
+			continue
+		}
+
+		var row []T
+		col := 0
+		for _, char := range line {
+			col++
+			v, err := cell(char)
+			if err != nil {
+				return Grid[T]{}, fmt.Errorf("line %d, column %d: %w", lineNum+1, col, err)
+			}
+			row = append(row, v)
+		}
+
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return Grid[T]{}, fmt.Errorf("line %d has %d cells, expected %d", lineNum+1, len(row), len(rows[0]))
+		}
+		rows = append(rows, row)
+	}
+
+	return FromRows(rows)
+}
+
+// ParseRunes parses a grid keeping every rune as is.
+func ParseRunes(data string) (Grid[rune], error) {
+	return Parse(data, func(r rune) (rune, error) {
+		return r, nil
+	})
+}
+
+func (g Grid[T]) Rows() int {
+	return g.rows
+}
+
+func (g Grid[T]) Cols() int {
+	return g.cols
+}
+
+func (g Grid[T]) InBounds(p Pos) bool {
+	return p.Row >= 0 && p.Row < g.rows && p.Col >= 0 && p.Col < g.cols
+}
+
+// At returns the cell at p, or false when p is out of bounds.
+func (g Grid[T]) At(p Pos) (T, bool) {
+	if !g.InBounds(p) {
+		var zero T
+		return zero, false
+	}
+	return g.cells[p.Row*g.cols+p.Col], true
+}
+
+// Set replaces the cell at p, returning false when p is out of bounds.
+func (g Grid[T]) Set(p Pos, v T) bool {
+	if !g.InBounds(p) {
+		return false
+	}
+	g.cells[p.Row*g.cols+p.Col] = v
+	return true
+}
+
+// All yields every cell with its position, row by row.
+func (g Grid[T]) All() iter.Seq2[Pos, T] {
+	return func(yield func(Pos, T) bool) {
+		for i, v := range g.cells {
+			if !yield(Pos{i / g.cols, i % g.cols}, v) {
+				return
+			}
+		}
+	}
+}
+
+// Neighbors yields the positions p+offset that are inside the grid.
+func (g Grid[T]) Neighbors(p Pos, offsets []Pos) iter.Seq[Pos] {
+	return func(yield func(Pos) bool) {
+		for _, offset := range offsets {
+			n := p.Add(offset)
+			if g.InBounds(n) && !yield(n) {
+				return
+			}
+		}
+	}
+}
+
+func (g Grid[T]) Neighbors4(p Pos) iter.Seq[Pos] {
+	return g.Neighbors(p, Directions4)
+}
+
+func (g Grid[T]) Neighbors8(p Pos) iter.Seq[Pos] {
+	return g.Neighbors(p, Directions8)
+}
+
+// Find returns the first position, row by row, that holds v.
+func (g Grid[T]) Find(v T) (Pos, bool) {
+	for p := range g.FindAll(v) {
+		return p, true
+	}
+	return Pos{}, false
+}
+
+func (g Grid[T]) FindAll(v T) iter.Seq[Pos] {
+	return func(yield func(Pos) bool) {
+		for p, cell := range g.All() {
+			if cell == v && !yield(p) {
+				return
+			}
+		}
+	}
+}
+
+// Row returns a copy of row r.
+func (g Grid[T]) Row(r int) []T {
+	if r < 0 || r >= g.rows {
+		return nil
+	}
+	out := make([]T, g.cols)
+	copy(out, g.cells[r*g.cols:(r+1)*g.cols])
+	return out
+}
+
+// Col returns a copy of column c.
+func (g Grid[T]) Col(c int) []T {
+	if c < 0 || c >= g.cols {
+		return nil
+	}
+	out := make([]T, g.rows)
+	for r := range g.rows {
+		out[r] = g.cells[r*g.cols+c]
+	}
+	return out
+}
+
+func (g Grid[T]) Clone() Grid[T] {
+	out := Grid[T]{rows: g.rows, cols: g.cols, cells: make([]T, len(g.cells))}
+	copy(out.cells, g.cells)
+	return out
+}
+
+func (g Grid[T]) Transpose() Grid[T] {
+	return g.remap(g.cols, g.rows, func(p Pos) Pos {
+		return Pos{p.Col, p.Row}
+	})
+}
+
+// RotateCW rotates the grid 90 degrees clockwise.
+func (g Grid[T]) RotateCW() Grid[T] {
+	return g.remap(g.cols, g.rows, func(p Pos) Pos {
+		return Pos{p.Col, g.rows - 1 - p.Row}
+	})
+}
+
+// RotateCCW rotates the grid 90 degrees counterclockwise.
+func (g Grid[T]) RotateCCW() Grid[T] {
+	return g.remap(g.cols, g.rows, func(p Pos) Pos {
+		return Pos{g.cols - 1 - p.Col, p.Row}
+	})
+}
+
+// remap builds a rows x cols grid where the cell at p moves to to(p).
+func (g Grid[T]) remap(rows, cols int, to func(Pos) Pos) Grid[T] {
+	out := Grid[T]{rows: rows, cols: cols, cells: make([]T, len(g.cells))}
+	for p, v := range g.All() {
+		out.Set(to(p), v)
+	}
+	return out
+}
+
+// Format prints the grid one row per line, using cell to print every cell.
+func (g Grid[T]) Format(cell func(T) string) string {
+	var b strings.Builder
+	for r := range g.rows {
+		for c := range g.cols {
+			b.WriteString(cell(g.cells[r*g.cols+c]))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func (g Grid[T]) String() string {
+	return g.Format(func(v T) string {
+		switch v := any(v).(type) {
+		case rune:
+			return string(v)
+		case byte:
+			return string(rune(v))
+		case fmt.Stringer:
+			return v.String()
+		}
+		return fmt.Sprint(v)
+	})
+}
@@ -0,0 +1,19 @@
+package grid
+
+import "testing"
+
+func TestParseKeepsSpaceRows(t *testing.T) {
+	g, err := ParseRunes("#.#\n   \n\n.#.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Rows() != 3 || g.Cols() != 3 {
+		t.Fatalf("got %dx%d grid, want 3x3", g.Rows(), g.Cols())
+	}
+	if r, _ := g.At(Pos{Row: 1, Col: 1}); r != ' ' {
+		t.Fatalf("At(1, 1) = %q, want ' '", r)
+	}
+	if r, _ := g.At(Pos{Row: 2, Col: 1}); r != '#' {
+		t.Fatalf("At(2, 1) = %q, want '#'", r)
+	}
+}
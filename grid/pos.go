@@ -0,0 +1,40 @@
+package grid
+
+type Pos struct {
+	Row int
+	Col int
+}
+
+// Offsets to the 4 orthogonal neighbours and to all the 8 surrounding cells.
+var (
+	Directions4 = []Pos{
+		{-1, 0},
+		{0, -1}, {0, 1},
+		{1, 0},
+	}
+	Directions8 = []Pos{
+		{-1, -1}, {-1, 0}, {-1, 1},
+		{0, -1}, {0, 1},
+		{1, -1}, {1, 0}, {1, 1},
+	}
+)
+
+func (p Pos) Add(other Pos) Pos {
+	return Pos{p.Row + other.Row, p.Col + other.Col}
+}
+
+func (p Pos) Up() Pos {
+	return Pos{p.Row - 1, p.Col}
+}
+
+func (p Pos) Down() Pos {
+	return Pos{p.Row + 1, p.Col}
+}
+
+func (p Pos) Left() Pos {
+	return Pos{p.Row, p.Col - 1}
+}
+
+func (p Pos) Right() Pos {
+	return Pos{p.Row, p.Col + 1}
+}
@@ -0,0 +1,82 @@
+package intervals
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jibaru/advent-of-code-2025/numeric"
+)
+
+// ErrOverflow is returned when a size does not fit in an int. It is
+// numeric.ErrOverflow, so either can be matched with errors.Is.
+var ErrOverflow = numeric.ErrOverflow
+
+// Range holds the integers from From to To, both included. A range with
+// From greater than To is empty.
+type Range struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("%d-%d", r.From, r.To)
+}
+
+func (r Range) Inside(id int) bool {
+	return id >= r.From && id <= r.To
+}
+
+func (r Range) Overlaps(other Range) bool {
+	if r.IsEmpty() || other.IsEmpty() {
+		return false
+	}
+	return r.From <= other.To && other.From <= r.To
+}
+
+// Touches reports whether the ranges overlap or are next to each other, like
+// 3-5 and 6-8, so that together they form a single block of integers.
+func (r Range) Touches(other Range) bool {
+	if r.IsEmpty() || other.IsEmpty() {
+		return false
+	}
+	return !r.endsBefore(other) && !other.endsBefore(r)
+}
+
+// Merge returns the smallest range that covers both ranges.
+func (r Range) Merge(other Range) Range {
+	if r.IsEmpty() {
+		return other
+	}
+	if other.IsEmpty() {
+		return r
+	}
+	return Range{
+		From: min(r.From, other.From),
+		To:   max(r.To, other.To),
+	}
+}
+
+// Size returns how many integers are in the range, or ErrOverflow when
+// there are more than math.MaxInt, like in math.MinInt-math.MaxInt.
+func (r Range) Size() (int, error) {
+	if r.IsEmpty() {
+		return 0, nil
+	}
+	// the difference is exact in uint64 because To >= From
+	diff := uint64(r.To) - uint64(r.From)
+	if diff >= math.MaxInt {
+		return 0, ErrOverflow
+	}
+	return int(diff) + 1, nil
+}
+
+func (r Range) IsEmpty() bool {
+	return r.From > r.To
+}
+
+// endsBefore reports whether r ends before other starts, leaving at least
+// one integer between them.
+func (r Range) endsBefore(other Range) bool {
+	// r.To < other.From rules out overflowing r.To+1
+	return r.To < other.From && r.To+1 < other.From
+}
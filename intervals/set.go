@@ -0,0 +1,242 @@
+package intervals
+
+import (
+	"cmp"
+	"iter"
+	"math"
+	"slices"
+	"sort"
+)
+
+// Set is a set of integers stored as sorted ranges that neither overlap nor
+// touch each other.
+type Set struct {
+	ranges []Range
+}
+
+func NewSet(ranges ...Range) *Set {
+	return &Set{ranges: normalize(slices.Clone(ranges))}
+}
+
+// Merge returns the ranges sorted, without the empty ones, and with the
+// overlapping ones merged. When adjacent is true, ranges that are next to
+// each other, like 3-5 and 6-8, are merged too.
+func Merge(ranges []Range, adjacent bool) []Range {
+	return mergeRanges(slices.Clone(ranges), adjacent)
+}
+
+// normalize merges the ranges the way a Set keeps them. It reuses the
+// given slice.
+func normalize(ranges []Range) []Range {
+	return mergeRanges(ranges, true)
+}
+
+func mergeRanges(ranges []Range, adjacent bool) []Range {
+	ranges = slices.DeleteFunc(ranges, Range.IsEmpty)
+	slices.SortFunc(ranges, func(a, b Range) int {
+		return cmp.Compare(a.From, b.From)
+	})
+
+	merged := ranges[:0]
+	for _, r := range ranges {
+		if n := len(merged); n > 0 {
+			last := merged[n-1]
+			if last.Overlaps(r) || (adjacent && last.Touches(r)) {
+				merged[n-1] = last.Merge(r)
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+func (s *Set) Insert(r Range) {
+	if r.IsEmpty() {
+		return
+	}
+
+	// ranges[i:j] are the ones that overlap or touch r
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return !s.ranges[i].endsBefore(r)
+	})
+	j := i
+	for j < len(s.ranges) && !r.endsBefore(s.ranges[j]) {
+		r = r.Merge(s.ranges[j])
+		j++
+	}
+
+	s.ranges = slices.Replace(s.ranges, i, j, r)
+}
+
+func (s *Set) Remove(r Range) {
+	if r.IsEmpty() {
+		return
+	}
+
+	// ranges[i:j] are the ones that overlap r
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].To >= r.From
+	})
+	j := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].From > r.To
+	})
+	if i >= j {
+		return
+	}
+
+	var rest []Range
+	if first := s.ranges[i]; first.From < r.From {
+		rest = append(rest, Range{From: first.From, To: r.From - 1})
+	}
+	if last := s.ranges[j-1]; last.To > r.To {
+		rest = append(rest, Range{From: r.To + 1, To: last.To})
+	}
+
+	s.ranges = slices.Replace(s.ranges, i, j, rest...)
+}
+
+// Contains finds the range that could hold id with a binary search.
+func (s *Set) Contains(id int) bool {
+	_, ok := s.Find(id)
+	return ok
+}
+
+// Find returns the range of the set that holds id.
+func (s *Set) Find(id int) (Range, bool) {
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].To >= id
+	})
+	if i < len(s.ranges) && s.ranges[i].Inside(id) {
+		return s.ranges[i], true
+	}
+	return Range{}, false
+}
+
+// ContainsAll reports, for every id, whether it is in the set.
+func (s *Set) ContainsAll(ids []int) []bool {
+	out := make([]bool, len(ids))
+	for i, id := range ids {
+		out[i] = s.Contains(id)
+	}
+	return out
+}
+
+// ContainsSeq yields every id with whether it is in the set, as they come.
+func (s *Set) ContainsSeq(ids iter.Seq[int]) iter.Seq2[int, bool] {
+	return func(yield func(int, bool) bool) {
+		for id := range ids {
+			if !yield(id, s.Contains(id)) {
+				return
+			}
+		}
+	}
+}
+
+// CountSorted counts the ids in the set with a single sweep over the ids
+// and the ranges. The ids must be sorted in ascending order.
+func (s *Set) CountSorted(ids []int) int {
+	count := 0
+	i := 0
+	for _, id := range ids {
+		for i < len(s.ranges) && s.ranges[i].To < id {
+			i++
+		}
+		if i == len(s.ranges) {
+			break
+		}
+		if s.ranges[i].Inside(id) {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *Set) Union(other *Set) *Set {
+	ranges := make([]Range, 0, len(s.ranges)+len(other.ranges))
+	ranges = append(ranges, s.ranges...)
+	ranges = append(ranges, other.ranges...)
+	return &Set{ranges: normalize(ranges)}
+}
+
+func (s *Set) Intersection(other *Set) *Set {
+	out := &Set{}
+	i, j := 0, 0
+	for i < len(s.ranges) && j < len(other.ranges) {
+		a, b := s.ranges[i], other.ranges[j]
+		if a.Overlaps(b) {
+			out.ranges = append(out.ranges, Range{From: max(a.From, b.From), To: min(a.To, b.To)})
+		}
+		// move past the range that ends first
+		if a.To < b.To {
+			i++
+		} else {
+			j++
+		}
+	}
+	return out
+}
+
+func (s *Set) Difference(other *Set) *Set {
+	return s.Intersection(other.Complement(Range{From: math.MinInt, To: math.MaxInt}))
+}
+
+// Complement returns the integers inside bounds that are not in the set.
+func (s *Set) Complement(bounds Range) *Set {
+	out := &Set{}
+	if bounds.IsEmpty() {
+		return out
+	}
+
+	next := bounds.From
+	for _, r := range s.ranges {
+		if r.To < next {
+			continue
+		}
+		if r.From > bounds.To {
+			break
+		}
+		if r.From > next {
+			out.ranges = append(out.ranges, Range{From: next, To: r.From - 1})
+		}
+		if r.To >= bounds.To {
+			return out
+		}
+		next = r.To + 1
+	}
+
+	out.ranges = append(out.ranges, Range{From: next, To: bounds.To})
+	return out
+}
+
+// Size returns how many integers are in the set, or ErrOverflow when there
+// are more than math.MaxInt.
+func (s *Set) Size() (int, error) {
+	size := 0
+	for _, r := range s.ranges {
+		n, err := r.Size()
+		if err != nil {
+			return 0, err
+		}
+		if size > math.MaxInt-n {
+			return 0, ErrOverflow
+		}
+		size += n
+	}
+	return size, nil
+}
+
+// Len returns how many ranges the set is made of.
+func (s *Set) Len() int {
+	return len(s.ranges)
+}
+
+// All yields the ranges of the set in ascending order.
+func (s *Set) All() iter.Seq[Range] {
+	return slices.Values(s.ranges)
+}
+
+// Ranges returns a copy of the ranges of the set in ascending order.
+func (s *Set) Ranges() []Range {
+	return slices.Clone(s.ranges)
+}
@@ -0,0 +1,144 @@
+package intervals
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// bitmapSize is the universe of the bitmap the sets are checked against.
+const bitmapSize = 64
+
+type bitmap []bool
+
+func (b bitmap) set(r Range, v bool) {
+	for i := max(r.From, 0); i <= min(r.To, bitmapSize-1); i++ {
+		b[i] = v
+	}
+}
+
+func (b bitmap) size() int {
+	n := 0
+	for _, v := range b {
+		if v {
+			n++
+		}
+	}
+	return n
+}
+
+// ranges returns the maximal runs of the bitmap, which is how a canonical
+// set must store it.
+func (b bitmap) ranges() []Range {
+	var out []Range
+	for i := 0; i < len(b); i++ {
+		if !b[i] {
+			continue
+		}
+		j := i
+		for j+1 < len(b) && b[j+1] {
+			j++
+		}
+		out = append(out, Range{From: i, To: j})
+		i = j
+	}
+	return out
+}
+
+func randomRange(r *rand.Rand) Range {
+	from := r.Intn(bitmapSize)
+	// empty ranges now and then
+	return Range{From: from, To: min(from+r.Intn(12)-2, bitmapSize-1)}
+}
+
+func randomSet(r *rand.Rand) (*Set, bitmap) {
+	s, b := NewSet(), make(bitmap, bitmapSize)
+	for range r.Intn(6) {
+		rng := randomRange(r)
+		s.Insert(rng)
+		b.set(rng, true)
+	}
+	return s, b
+}
+
+func checkSet(t *testing.T, step string, s *Set, want bitmap) {
+	t.Helper()
+	if got, want := s.Ranges(), want.ranges(); !slices.Equal(got, want) {
+		t.Fatalf("%s: ranges %v, want %v", step, got, want)
+	}
+	for i := -1; i <= bitmapSize; i++ {
+		in := i >= 0 && i < bitmapSize && want[i]
+		if s.Contains(i) != in {
+			t.Fatalf("%s: Contains(%d) = %v, want %v", step, i, !in, in)
+		}
+	}
+	size, err := s.Size()
+	if err != nil || size != want.size() {
+		t.Fatalf("%s: Size() = %d, %v, want %d", step, size, err, want.size())
+	}
+}
+
+func TestSetMatchesBitmap(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 2000 {
+		s, b := randomSet(r)
+		for range 20 {
+			var step string
+			switch r.Intn(6) {
+			case 0:
+				rng := randomRange(r)
+				step = "Insert " + rng.String()
+				s.Insert(rng)
+				b.set(rng, true)
+			case 1:
+				rng := randomRange(r)
+				step = "Remove " + rng.String()
+				s.Remove(rng)
+				b.set(rng, false)
+			case 2:
+				other, ob := randomSet(r)
+				step = "Union"
+				s = s.Union(other)
+				for i := range b {
+					b[i] = b[i] || ob[i]
+				}
+			case 3:
+				other, ob := randomSet(r)
+				step = "Intersection"
+				s = s.Intersection(other)
+				for i := range b {
+					b[i] = b[i] && ob[i]
+				}
+			case 4:
+				other, ob := randomSet(r)
+				step = "Difference"
+				s = s.Difference(other)
+				for i := range b {
+					b[i] = b[i] && !ob[i]
+				}
+			case 5:
+				bounds := randomRange(r)
+				step = "Complement " + bounds.String()
+				s = s.Complement(bounds)
+				nb := make(bitmap, bitmapSize)
+				for i := range nb {
+					nb[i] = bounds.Inside(i) && !b[i]
+				}
+				b = nb
+			}
+			checkSet(t, step, s, b)
+		}
+	}
+}
+
+func TestSetSizeOverflow(t *testing.T) {
+	s := NewSet(Range{From: math.MinInt, To: math.MaxInt})
+	if _, err := s.Size(); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("Size() error = %v, want ErrOverflow", err)
+	}
+	if got := s.Complement(Range{From: 0, To: 10}).Len(); got != 0 {
+		t.Fatalf("complement of everything has %d ranges, want 0", got)
+	}
+}
@@ -0,0 +1,219 @@
+package intervals
+
+import (
+	"iter"
+	"math"
+	"math/rand/v2"
+)
+
+// Tree is a set of integers, like Set, that stays fast under constant
+// change: its disjoint ranges live in a treap, so inserting or removing a
+// range and looking up an id take logarithmic expected time, and the
+// number of integers in the set is kept up to date as ranges come and go.
+type Tree struct {
+	root *node
+	// count is the number of ranges and diffs the sum of To-From over
+	// them. diffs never exceeds math.MaxUint64 because the ranges are
+	// disjoint.
+	count int
+	diffs uint64
+}
+
+type node struct {
+	r        Range
+	priority uint64
+	left     *node
+	right    *node
+}
+
+func NewTree(ranges ...Range) *Tree {
+	t := &Tree{}
+	for _, r := range ranges {
+		t.Insert(r)
+	}
+	return t
+}
+
+func (t *Tree) Insert(r Range) {
+	if r.IsEmpty() {
+		return
+	}
+
+	left, rest := split(t.root, func(n Range) bool {
+		return n.From < r.From
+	})
+
+	// the last range before r can reach it
+	if last := maxNode(left); last != nil && !last.r.endsBefore(r) {
+		left = removeMax(left)
+		t.forget(last.r)
+		r = r.Merge(last.r)
+	}
+
+	touching, right := split(rest, func(n Range) bool {
+		return !r.endsBefore(n)
+	})
+	for n := range nodes(touching) {
+		t.forget(n.r)
+		r = r.Merge(n.r)
+	}
+
+	t.remember(r)
+	t.root = join(join(left, &node{r: r, priority: rand.Uint64()}), right)
+}
+
+func (t *Tree) Remove(r Range) {
+	if r.IsEmpty() {
+		return
+	}
+
+	left, rest := split(t.root, func(n Range) bool {
+		return n.From < r.From
+	})
+	overlapping, right := split(rest, func(n Range) bool {
+		return n.From <= r.To
+	})
+
+	var kept []Range
+	if last := maxNode(left); last != nil && last.r.To >= r.From {
+		left = removeMax(left)
+		t.forget(last.r)
+		kept = append(kept, Range{From: last.r.From, To: r.From - 1})
+		if last.r.To > r.To {
+			kept = append(kept, Range{From: r.To + 1, To: last.r.To})
+		}
+	}
+	for n := range nodes(overlapping) {
+		t.forget(n.r)
+		if n.r.To > r.To {
+			kept = append(kept, Range{From: r.To + 1, To: n.r.To})
+		}
+	}
+
+	for _, k := range kept {
+		t.remember(k)
+		left = join(left, &node{r: k, priority: rand.Uint64()})
+	}
+	t.root = join(left, right)
+}
+
+func (t *Tree) Contains(id int) bool {
+	_, ok := t.Find(id)
+	return ok
+}
+
+// Find returns the range of the tree that holds id.
+func (t *Tree) Find(id int) (Range, bool) {
+	var floor *node
+	for n := t.root; n != nil; {
+		if n.r.From <= id {
+			floor = n
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	if floor != nil && floor.r.Inside(id) {
+		return floor.r, true
+	}
+	return Range{}, false
+}
+
+// Total returns how many integers are in the tree, or ErrOverflow when
+// there are more than math.MaxInt.
+func (t *Tree) Total() (int, error) {
+	if t.diffs > uint64(math.MaxInt-t.count) {
+		return 0, ErrOverflow
+	}
+	return int(t.diffs) + t.count, nil
+}
+
+// Len returns how many ranges the tree is made of.
+func (t *Tree) Len() int {
+	return t.count
+}
+
+// All yields the ranges of the tree in ascending order.
+func (t *Tree) All() iter.Seq[Range] {
+	return func(yield func(Range) bool) {
+		for n := range nodes(t.root) {
+			if !yield(n.r) {
+				return
+			}
+		}
+	}
+}
+
+func (t *Tree) remember(r Range) {
+	t.count++
+	t.diffs += uint64(r.To) - uint64(r.From)
+}
+
+func (t *Tree) forget(r Range) {
+	t.count--
+	t.diffs -= uint64(r.To) - uint64(r.From)
+}
+
+// split separates the ranges for which goesLeft is true from the rest.
+// goesLeft must be true for a prefix of the ranges in order.
+func split(n *node, goesLeft func(Range) bool) (*node, *node) {
+	if n == nil {
+		return nil, nil
+	}
+	if goesLeft(n.r) {
+		l, r := split(n.right, goesLeft)
+		n.right = l
+		return n, r
+	}
+	l, r := split(n.left, goesLeft)
+	n.left = r
+	return l, n
+}
+
+// join merges two treaps where every range of a comes before every range of b.
+func join(a, b *node) *node {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		a.right = join(a.right, b)
+		return a
+	}
+	b.left = join(a, b.left)
+	return b
+}
+
+func maxNode(n *node) *node {
+	if n == nil {
+		return nil
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return n
+}
+
+func removeMax(n *node) *node {
+	if n.right == nil {
+		return n.left
+	}
+	n.right = removeMax(n.right)
+	return n
+}
+
+// nodes yields the nodes of a treap in order.
+func nodes(n *node) iter.Seq[*node] {
+	return func(yield func(*node) bool) {
+		walk(n, yield)
+	}
+}
+
+func walk(n *node, yield func(*node) bool) bool {
+	if n == nil {
+		return true
+	}
+	return walk(n.left, yield) && yield(n) && walk(n.right, yield)
+}
@@ -0,0 +1,40 @@
+package intervals
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestTreeMatchesBitmap(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for range 2000 {
+		tree, b := NewTree(), make(bitmap, bitmapSize)
+		for range 20 {
+			rng := randomRange(r)
+			if r.Intn(3) == 0 {
+				tree.Remove(rng)
+				b.set(rng, false)
+			} else {
+				tree.Insert(rng)
+				b.set(rng, true)
+			}
+
+			if got, want := slices.Collect(tree.All()), b.ranges(); !slices.Equal(got, want) {
+				t.Fatalf("after %v: ranges %v, want %v", rng, got, want)
+			}
+			if tree.Len() != len(b.ranges()) {
+				t.Fatalf("Len() = %d, want %d", tree.Len(), len(b.ranges()))
+			}
+			for i := range bitmapSize {
+				if tree.Contains(i) != b[i] {
+					t.Fatalf("Contains(%d) = %v, want %v", i, !b[i], b[i])
+				}
+			}
+			total, err := tree.Total()
+			if err != nil || total != b.size() {
+				t.Fatalf("Total() = %d, %v, want %d", total, err, b.size())
+			}
+		}
+	}
+}
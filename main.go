@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
+	"os"
 
 	day0 "github.com/jibaru/advent-of-code-2025/day_0"
 	day1 "github.com/jibaru/advent-of-code-2025/day_1"
@@ -20,6 +22,19 @@ func main() {
 	day := flag.Int("d", 0, "Specify the day")
 	part := flag.Int("p", 1, "Specify part of the day (1 or 2)")
 	isTest := flag.Bool("t", false, "Specify is the input is test")
+	useBig := flag.Bool("big", false, "Use arbitrary-precision arithmetic when supported by the day")
+	base := flag.Int("base", 10, "Day 2: base in which invalid IDs are checked for repeats")
+	repeats := flag.String("repeats", "", "Day 2: repeat count of invalid IDs: k, k+ or a-b (default depends on the part)")
+	anim := flag.String("anim", "", "Day 4: write the part 2 removal rounds as an animation: ansi, text or gif")
+	report := flag.String("report", "", "Day 5: write why every part 1 ID is fresh or spoiled: text, csv or json")
+	separateAdjacent := flag.Bool("separate-adjacent", false, "Day 5: keep adjacent ranges like 3-5 and 6-8 apart in the report")
+	out := flag.String("out", "", "File where animations and reports are written (default stdout)")
+	explain := flag.Bool("explain", false, "Print how the answer was built when supported by the day")
+	k := flag.Int("k", 0, "Day 3: number of batteries turned on per bank in part 2 (default 12)")
+	verify := flag.Bool("verify", false, "Day 3: check every selection against brute force on small banks")
+	stream := flag.Bool("stream", false, "Day 6: read the worksheet one problem at a time instead of loading it")
+	strict := flag.Bool("strict", false, "Day 6: fail on the problem that overflows an int instead of switching to math/big")
+	mod := flag.Int("mod", 0, "Day 7: count the part 2 timelines modulo this number")
 
 	flag.Parse()
 
@@ -31,17 +46,44 @@ func main() {
 	case 1:
 		answer, err = day1.Solve(*part, *isTest)
 	case 2:
-		answer, err = day2.Solve(*part, *isTest)
+		opts := day2.Options{Big: *useBig}
+		if *repeats != "" {
+			opts.Rule, err = day2.ParseRepeatRule(*repeats)
+		}
+		opts.Rule.Base = *base
+		if err == nil {
+			answer, err = day2.SolveWithOptions(*part, *isTest, opts)
+		}
 	case 3:
-		answer, err = day3.Solve(*part, *isTest)
+		opts := day3.Options{Verify: *verify, K: *k}
+		if *explain {
+			opts.Explain = os.Stdout
+		}
+		answer, err = day3.SolveWithOptions(*part, *isTest, opts)
 	case 4:
-		answer, err = day4.Solve(*part, *isTest)
+		opts := day4.Options{AnimationFormat: day4.AnimationFormat(*anim)}
+		if *anim != "" {
+			opts.Animation, err = output(*out)
+		}
+		if err == nil {
+			answer, err = day4.SolveWithOptions(*part, *isTest, opts)
+		}
 	case 5:
-		answer, err = day5.Solve(*part, *isTest)
+		opts := day5.Options{ReportFormat: day5.ReportFormat(*report), SeparateAdjacent: *separateAdjacent}
+		if *report != "" {
+			opts.Report, err = output(*out)
+		}
+		if err == nil {
+			answer, err = day5.SolveWithOptions(*part, *isTest, opts)
+		}
 	case 6:
-		answer, err = day6.Solve(*part, *isTest)
+		opts := day6.Options{Big: *useBig, Strict: *strict, Stream: *stream}
+		if *explain {
+			opts.Explain = os.Stdout
+		}
+		answer, err = day6.SolveWithOptions(*part, *isTest, opts)
 	case 7:
-		answer, err = day7.Solve(*part, *isTest)
+		answer, err = day7.SolveWithOptions(*part, *isTest, day7.Options{Big: *useBig, Modulus: *mod})
 	case 8:
 		answer, err = day8.Solve(*part, *isTest)
 	case 9:
@@ -56,3 +98,12 @@ func main() {
 		fmt.Printf("answer for day %v part %v: %v\n", *day, *part, answer)
 	}
 }
+
+// output opens the file where extra output is written, or returns stdout
+// when path is empty. The file is left for the process exit to close.
+func output(path string) (io.Writer, error) {
+	if path == "" {
+		return os.Stdout, nil
+	}
+	return os.Create(path)
+}
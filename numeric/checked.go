@@ -0,0 +1,39 @@
+package numeric
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrOverflow is returned when a result does not fit in an int.
+var ErrOverflow = errors.New("integer overflow")
+
+func Add(a, b int) (int, error) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, ErrOverflow
+	}
+	return sum, nil
+}
+
+func Sub(a, b int) (int, error) {
+	diff := a - b
+	if (b > 0 && diff > a) || (b < 0 && diff < a) {
+		return 0, ErrOverflow
+	}
+	return diff, nil
+}
+
+func Mul(a, b int) (int, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	if (a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) {
+		return 0, ErrOverflow
+	}
+	prod := a * b
+	if prod/b != a {
+		return 0, ErrOverflow
+	}
+	return prod, nil
+}
@@ -0,0 +1,68 @@
+package numeric
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestChecked(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(a, b int) (int, error)
+		a, b int
+		want int
+		ok   bool
+	}{
+		{"add", Add, 2, 3, 5, true},
+		{"add max", Add, math.MaxInt, 1, 0, false},
+		{"add min", Add, math.MinInt, -1, 0, false},
+		{"sub", Sub, 2, 3, -1, true},
+		{"sub min", Sub, math.MinInt, 1, 0, false},
+		{"sub max", Sub, 0, math.MinInt, 0, false},
+		{"mul", Mul, -4, 5, -20, true},
+		{"mul zero", Mul, math.MinInt, 0, 0, true},
+		{"mul max", Mul, math.MaxInt/2 + 1, 2, 0, false},
+		{"mul min by -1", Mul, math.MinInt, -1, 0, false},
+		{"mul -1 by min", Mul, -1, math.MinInt, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.op(tt.a, tt.b)
+		if tt.ok && (err != nil || got != tt.want) {
+			t.Errorf("%s(%d, %d) = %d, %v, want %d", tt.name, tt.a, tt.b, got, err, tt.want)
+		}
+		if !tt.ok && !errors.Is(err, ErrOverflow) {
+			t.Errorf("%s(%d, %d) error = %v, want ErrOverflow", tt.name, tt.a, tt.b, err)
+		}
+	}
+}
+
+func TestTotalSwitchesToBig(t *testing.T) {
+	var total Total
+	total.Add(math.MaxInt)
+	if total.IsBig() || total.Result() != math.MaxInt {
+		t.Fatalf("Result() = %v, want %d in an int", total.Result(), math.MaxInt)
+	}
+
+	total.Add(2)
+	want := new(big.Int).Add(big.NewInt(math.MaxInt), big.NewInt(2))
+	got, ok := total.Result().(*big.Int)
+	if !ok || got.Cmp(want) != 0 {
+		t.Fatalf("Result() = %v, want %v", total.Result(), want)
+	}
+
+	total.AddBig(big.NewInt(-2))
+	if got := total.Result().(*big.Int); !got.IsInt64() || got.Int64() != math.MaxInt {
+		t.Fatalf("Result() = %v, want %d", got, math.MaxInt)
+	}
+}
+
+func TestTotalUseBig(t *testing.T) {
+	var total Total
+	total.UseBig()
+	if got, ok := total.Result().(*big.Int); !ok || got.Sign() != 0 {
+		t.Fatalf("Result() = %v, want a zero *big.Int", total.Result())
+	}
+}
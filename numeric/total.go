@@ -0,0 +1,47 @@
+package numeric
+
+import "math/big"
+
+// Total is a sum kept in an int until it no longer fits, and in math/big
+// from then on. The zero value is an empty sum.
+type Total struct {
+	small int
+	big   *big.Int
+}
+
+// UseBig moves the sum to math/big right away, so Result is a *big.Int
+// even when it would fit in an int.
+func (t *Total) UseBig() {
+	if t.big == nil {
+		t.big = big.NewInt(int64(t.small))
+	}
+}
+
+func (t *Total) Add(n int) {
+	if t.big == nil {
+		if sum, err := Add(t.small, n); err == nil {
+			t.small = sum
+			return
+		}
+		t.UseBig()
+	}
+	t.big.Add(t.big, big.NewInt(int64(n)))
+}
+
+func (t *Total) AddBig(n *big.Int) {
+	t.UseBig()
+	t.big.Add(t.big, n)
+}
+
+// IsBig reports whether the sum is kept in math/big.
+func (t *Total) IsBig() bool {
+	return t.big != nil
+}
+
+// Result returns an int, or a *big.Int once the sum is kept in math/big.
+func (t *Total) Result() any {
+	if t.big != nil {
+		return t.big
+	}
+	return t.small
+}